@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/plc"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/redis/go-redis/v9"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
+)
+
+// loadConfigFile sets every flag that wasn't set on the command line or through its env var
+// from the YAML config file, whose keys are flag names
+func loadConfigFile(cctx *cli.Context) error {
+	path := cctx.String("config")
+	if path == "" {
+		return nil
+	}
+
+	values, err := readConfigFile(cctx, path)
+	if err != nil {
+		return err
+	}
+
+	for name, vals := range values {
+		if cctx.IsSet(name) {
+			continue
+		}
+		for _, val := range vals {
+			if err := cctx.Set(name, val); err != nil {
+				return fmt.Errorf("invalid value for %s in config file: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func readConfigFile(cctx *cli.Context, path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, f := range cctx.App.Flags {
+		for _, name := range f.Names() {
+			known[name] = true
+		}
+	}
+
+	values := make(map[string][]string, len(raw))
+	for name, val := range raw {
+		if !known[name] || name == "config" {
+			return nil, fmt.Errorf("unknown setting %q in config file", name)
+		}
+
+		switch val := val.(type) {
+		case nil:
+		case []interface{}:
+			for _, item := range val {
+				values[name] = append(values[name], fmt.Sprint(item))
+			}
+		case map[string]interface{}:
+			return nil, fmt.Errorf("setting %q in config file must be a value or a list", name)
+		default:
+			values[name] = []string{fmt.Sprint(val)}
+		}
+	}
+
+	return values, nil
+}
+
+// reloadConfig layers the flags again from scratch, so the config file is re-read
+// while the command line and env vars keep taking precedence over it
+func reloadConfig(cctx *cli.Context) (*cli.Context, error) {
+	set := flag.NewFlagSet(cctx.App.Name, flag.ContinueOnError)
+	for _, f := range cctx.App.Flags {
+		if err := f.Apply(set); err != nil {
+			return nil, err
+		}
+	}
+	if err := set.Parse(os.Args[1:]); err != nil {
+		return nil, err
+	}
+
+	reloaded := cli.NewContext(cctx.App, set, nil)
+	if err := loadConfigFile(reloaded); err != nil {
+		return nil, err
+	}
+
+	return reloaded, nil
+}
+
+// applySettings applies the settings that are safe to change while running, at startup and on every reload.
+// Nothing is applied unless every setting is valid.
+func applySettings(cctx *cli.Context, directory *plc.Directory, st *store.Store) error {
+	settings := plc.DefaultSettings
+	settings.CheckPeriod = cctx.Duration("plc-check-period")
+	settings.ValidationPageSize = cctx.Int("validation-page-size")
+	settings.ValidationConcurrency = cctx.Int("validation-concurrency")
+	settings.ValidationLease = cctx.Duration("validation-lease")
+	settings.RevalidationConcurrency = cctx.Int("revalidation-concurrency")
+	settings.Schedule.ValidInterval = cctx.Duration("validation-interval")
+	settings.Schedule.InvalidInterval = cctx.Duration("validation-invalid-interval")
+
+	if settings.CheckPeriod <= 0 {
+		return fmt.Errorf("plc-check-period must be positive")
+	}
+	if settings.ValidationPageSize < 1 || settings.ValidationConcurrency < 1 {
+		return fmt.Errorf("validation-page-size and validation-concurrency must be at least 1")
+	}
+	if settings.RevalidationConcurrency < 1 {
+		return fmt.Errorf("revalidation-concurrency must be at least 1")
+	}
+	if settings.ValidationLease < time.Second {
+		return fmt.Errorf("validation-lease must be at least a second")
+	}
+	if settings.Schedule.ValidInterval <= 0 || settings.Schedule.InvalidInterval <= 0 {
+		return fmt.Errorf("validation-interval and validation-invalid-interval must be positive")
+	}
+	if cctx.Int("postgres-max-open-conns") < 0 {
+		return fmt.Errorf("postgres-max-open-conns can't be negative")
+	}
+
+	overrides, err := plc.ParseDomainLimits(cctx.StringSlice("validation-domain-rate-override"))
+	if err != nil {
+		return err
+	}
+	limits := map[string]rate.Limit{}
+	for domain, limit := range plc.DefaultDomainLimitOverrides {
+		limits[domain] = limit
+	}
+	for domain, limit := range overrides {
+		limits[domain] = limit
+	}
+
+	directory.Configure(settings)
+	directory.Upstreams.SetLimit(cctx.Float64("plc-rate-limit"))
+	directory.DomainLimiter.SetLimits(rate.Limit(cctx.Float64("validation-domain-rate")), cctx.Int("validation-domain-burst"), limits)
+	st.DB.SetMaxOpenConns(cctx.Int("postgres-max-open-conns"))
+
+	return nil
+}
+
+func newRedisClient(cctx *cli.Context) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cctx.String("redis-address"),
+		Password: cctx.String("redis-password"),
+		DB:       cctx.Int("redis-db"),
+		PoolSize: cctx.Int("redis-pool-size"),
+	})
+}
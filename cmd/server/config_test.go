@@ -0,0 +1,169 @@
+package main
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/plc"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/urfave/cli/v2"
+)
+
+// parseArgs runs the app's flag and config file handling on args, which also become os.Args for reloads
+func parseArgs(t *testing.T, args ...string) *cli.Context {
+	t.Helper()
+
+	args = append([]string{"bingo"}, args...)
+	prev := os.Args
+	os.Args = args
+	t.Cleanup(func() { os.Args = prev })
+
+	var parsed *cli.Context
+	app := newApp()
+	app.Action = func(cctx *cli.Context) error {
+		parsed = cctx
+		return nil
+	}
+	if err := app.Run(args); err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func writeConfig(t *testing.T, path string, config string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestDirectory returns a directory and store that applySettings can configure without connecting to anything
+func newTestDirectory(t *testing.T) (*plc.Directory, *store.Store) {
+	t.Helper()
+
+	upstreams, err := plc.NewUpstreamPool([]string{"https://plc.test"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("postgres", "postgres://localhost/bingo?sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &plc.Directory{
+		Upstreams:     upstreams,
+		DomainLimiter: plc.NewDomainLimiter(plc.DefaultDomainLimit, 1, nil),
+	}, &store.Store{DB: db}
+}
+
+func TestReloadConfigKeepsPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bingo.yaml")
+	writeConfig(t, path, `
+validation-page-size: 300
+validation-concurrency: 40
+validation-lease: 5m
+`)
+	t.Setenv("VALIDATION_LEASE", "2m")
+
+	cctx := parseArgs(t, "--config", path, "--validation-concurrency", "7")
+	if got := cctx.Int("validation-page-size"); got != 300 {
+		t.Errorf("validation-page-size = %d, want 300 from the file", got)
+	}
+	if got := cctx.Int("validation-concurrency"); got != 7 {
+		t.Errorf("validation-concurrency = %d, want 7 from the flag", got)
+	}
+	if got := cctx.Duration("validation-lease"); got != 2*time.Minute {
+		t.Errorf("validation-lease = %s, want 2m from the env var", got)
+	}
+
+	writeConfig(t, path, `
+validation-page-size: 500
+validation-concurrency: 41
+validation-lease: 6m
+revalidation-concurrency: 3
+`)
+	reloaded, err := reloadConfig(cctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Int("validation-page-size"); got != 500 {
+		t.Errorf("validation-page-size after reload = %d, want 500 from the file", got)
+	}
+	if got := reloaded.Int("revalidation-concurrency"); got != 3 {
+		t.Errorf("revalidation-concurrency after reload = %d, want 3 from the file", got)
+	}
+	if got := reloaded.Int("validation-concurrency"); got != 7 {
+		t.Errorf("validation-concurrency after reload = %d, want 7 from the flag", got)
+	}
+	if got := reloaded.Duration("validation-lease"); got != 2*time.Minute {
+		t.Errorf("validation-lease after reload = %s, want 2m from the env var", got)
+	}
+}
+
+func TestInvalidReloadKeepsSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bingo.yaml")
+	writeConfig(t, path, "validation-page-size: 300\n")
+
+	cctx := parseArgs(t, "--config", path)
+	directory, st := newTestDirectory(t)
+	if err := applySettings(cctx, directory, st); err != nil {
+		t.Fatal(err)
+	}
+	want := directory.Settings()
+	if want.ValidationPageSize != 300 {
+		t.Fatalf("validation page size = %d, want 300", want.ValidationPageSize)
+	}
+
+	// A file that can't be read again or holds an invalid value changes nothing, like a SIGHUP would
+	for _, config := range []string{
+		"validation-page-size: [",
+		"validation-page-size: lots\n",
+		"no-such-setting: 1\n",
+		"validation-page-size: 500\nvalidation-lease: 10ms\n",
+	} {
+		writeConfig(t, path, config)
+		reloaded, err := reloadConfig(cctx)
+		if err == nil {
+			err = applySettings(reloaded, directory, st)
+		}
+		if err == nil {
+			t.Errorf("reload of %q succeeded", config)
+		}
+		if got := directory.Settings(); got != want {
+			t.Errorf("settings after reload of %q = %+v, want %+v", config, got, want)
+		}
+	}
+}
+
+func TestApplySettingsRejectsInvalidValues(t *testing.T) {
+	tests := [][]string{
+		{"--plc-check-period", "0s"},
+		{"--validation-page-size", "0"},
+		{"--validation-concurrency", "0"},
+		{"--revalidation-concurrency", "0"},
+		{"--validation-lease", "500ms"},
+		{"--validation-interval", "0s"},
+		{"--validation-invalid-interval", "-1h"},
+		{"--postgres-max-open-conns", "-1"},
+		{"--validation-domain-rate-override", "bsky.social"},
+	}
+
+	for _, args := range tests {
+		directory, st := newTestDirectory(t)
+		if err := applySettings(parseArgs(t, args...), directory, st); err == nil {
+			t.Errorf("applySettings accepted %v", args)
+		}
+		if got := directory.Settings(); got != (plc.Settings{}) {
+			t.Errorf("applySettings with %v changed the settings to %+v", args, got)
+		}
+	}
+
+	directory, st := newTestDirectory(t)
+	if err := applySettings(parseArgs(t), directory, st); err != nil {
+		t.Errorf("applySettings rejected the defaults: %v", err)
+	}
+}
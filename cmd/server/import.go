@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ericvolp12/bingo/pkg/plc"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+// ImportPLC loads a local PLC export snapshot into the store and sets the ingestion cursor
+func ImportPLC(cctx *cli.Context) error {
+	ctx := cctx.Context
+
+	if cctx.NArg() != 1 {
+		return fmt.Errorf("expected exactly one export file argument")
+	}
+
+	rawlog, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failed to create logger: %+v\n", err)
+	}
+	defer rawlog.Sync()
+
+	log := rawlog.Sugar().With("source", "bingo_import_plc")
+
+	redisClient := newRedisClient(cctx)
+	defer redisClient.Close()
+
+	// Ping the redis server to make sure it's up.
+	_, err = redisClient.Ping(ctx).Result()
+	if err != nil {
+		return err
+	}
+
+	st, err := store.NewStore(ctx, redisClient, cctx.String("redis-prefix"), cctx.String("postgres-url"))
+	if err != nil {
+		return err
+	}
+	defer st.DB.Close()
+
+	dir, err := plc.NewDirectory(cctx.StringSlice("plc-endpoint"), redisClient, st, cctx.String("redis-prefix"))
+	if err != nil {
+		return err
+	}
+
+	dir.AuditOperations = cctx.Bool("audit-plc-operations")
+
+	log.Infof("importing PLC export from %s", cctx.Args().First())
+
+	return dir.ImportFile(ctx, cctx.Args().First())
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+// NormalizeHandles rewrites the handles stored before handles were normalized, it only needs to run once
+// after upgrading but is safe to run again
+func NormalizeHandles(cctx *cli.Context) error {
+	ctx := cctx.Context
+
+	rawlog, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failed to create logger: %+v\n", err)
+	}
+	defer rawlog.Sync()
+
+	log := rawlog.Sugar().With("source", "bingo_normalize_handles")
+
+	redisClient := newRedisClient(cctx)
+	defer redisClient.Close()
+
+	// Ping the redis server to make sure it's up.
+	_, err = redisClient.Ping(ctx).Result()
+	if err != nil {
+		return err
+	}
+
+	st, err := store.NewStore(ctx, redisClient, cctx.String("redis-prefix"), cctx.String("postgres-url"))
+	if err != nil {
+		return err
+	}
+	defer st.DB.Close()
+
+	log.Info("normalizing stored handles")
+
+	entries, operations, err := st.NormalizeHandles(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("normalized stored handles", "entries", entries, "operations", operations)
+
+	return nil
+}
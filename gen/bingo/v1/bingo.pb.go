@@ -10,6 +10,7 @@ import (
 	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,12 +23,112 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ValidationStatus classifies the outcome of the last validation of a handle
+type ValidationStatus int32
+
+const (
+	// The handle hasn't been validated since it was set
+	ValidationStatus_VALIDATION_STATUS_UNSPECIFIED ValidationStatus = 0
+	ValidationStatus_VALIDATION_STATUS_VALID       ValidationStatus = 1
+	// The DID document's alsoKnownAs doesn't claim the handle
+	ValidationStatus_VALIDATION_STATUS_UNCLAIMED ValidationStatus = 2
+	// The DID document couldn't be loaded, is_valid keeps the outcome of the last check
+	ValidationStatus_VALIDATION_STATUS_DOCUMENT_ERROR ValidationStatus = 3
+	// The handle's domain doesn't exist
+	ValidationStatus_VALIDATION_STATUS_NXDOMAIN ValidationStatus = 4
+	// _atproto TXT records exist but none point at the DID
+	ValidationStatus_VALIDATION_STATUS_TXT_MISMATCH ValidationStatus = 5
+	// The TXT lookup failed for a reason other than the name not existing
+	ValidationStatus_VALIDATION_STATUS_DNS_ERROR ValidationStatus = 6
+	// /.well-known/atproto-did responded with a non-200 status
+	ValidationStatus_VALIDATION_STATUS_HTTP_STATUS ValidationStatus = 7
+	// The handle's HTTPS certificate or handshake was invalid
+	ValidationStatus_VALIDATION_STATUS_TLS_ERROR ValidationStatus = 8
+	// The HTTPS request timed out
+	ValidationStatus_VALIDATION_STATUS_TIMEOUT ValidationStatus = 9
+	// The HTTPS request failed to connect for another reason
+	ValidationStatus_VALIDATION_STATUS_CONNECTION_ERROR ValidationStatus = 10
+	// /.well-known/atproto-did was served but didn't contain the DID
+	ValidationStatus_VALIDATION_STATUS_BODY_MISMATCH ValidationStatus = 11
+	// /.well-known/atproto-did was larger than the fetcher accepts
+	ValidationStatus_VALIDATION_STATUS_BODY_TOO_LARGE ValidationStatus = 12
+	// The name looked up exists but has no _atproto TXT records, unlike NXDOMAIN
+	ValidationStatus_VALIDATION_STATUS_NO_RECORD ValidationStatus = 13
+)
+
+// Enum value maps for ValidationStatus.
+var (
+	ValidationStatus_name = map[int32]string{
+		0:  "VALIDATION_STATUS_UNSPECIFIED",
+		1:  "VALIDATION_STATUS_VALID",
+		2:  "VALIDATION_STATUS_UNCLAIMED",
+		3:  "VALIDATION_STATUS_DOCUMENT_ERROR",
+		4:  "VALIDATION_STATUS_NXDOMAIN",
+		5:  "VALIDATION_STATUS_TXT_MISMATCH",
+		6:  "VALIDATION_STATUS_DNS_ERROR",
+		7:  "VALIDATION_STATUS_HTTP_STATUS",
+		8:  "VALIDATION_STATUS_TLS_ERROR",
+		9:  "VALIDATION_STATUS_TIMEOUT",
+		10: "VALIDATION_STATUS_CONNECTION_ERROR",
+		11: "VALIDATION_STATUS_BODY_MISMATCH",
+		12: "VALIDATION_STATUS_BODY_TOO_LARGE",
+		13: "VALIDATION_STATUS_NO_RECORD",
+	}
+	ValidationStatus_value = map[string]int32{
+		"VALIDATION_STATUS_UNSPECIFIED":      0,
+		"VALIDATION_STATUS_VALID":            1,
+		"VALIDATION_STATUS_UNCLAIMED":        2,
+		"VALIDATION_STATUS_DOCUMENT_ERROR":   3,
+		"VALIDATION_STATUS_NXDOMAIN":         4,
+		"VALIDATION_STATUS_TXT_MISMATCH":     5,
+		"VALIDATION_STATUS_DNS_ERROR":        6,
+		"VALIDATION_STATUS_HTTP_STATUS":      7,
+		"VALIDATION_STATUS_TLS_ERROR":        8,
+		"VALIDATION_STATUS_TIMEOUT":          9,
+		"VALIDATION_STATUS_CONNECTION_ERROR": 10,
+		"VALIDATION_STATUS_BODY_MISMATCH":    11,
+		"VALIDATION_STATUS_BODY_TOO_LARGE":   12,
+		"VALIDATION_STATUS_NO_RECORD":        13,
+	}
+)
+
+func (x ValidationStatus) Enum() *ValidationStatus {
+	p := new(ValidationStatus)
+	*p = x
+	return p
+}
+
+func (x ValidationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ValidationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_bingo_v1_bingo_proto_enumTypes[0].Descriptor()
+}
+
+func (ValidationStatus) Type() protoreflect.EnumType {
+	return &file_bingo_v1_bingo_proto_enumTypes[0]
+}
+
+func (x ValidationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ValidationStatus.Descriptor instead.
+func (ValidationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{0}
+}
+
 type LookupRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// A DID or a handle, handles are matched case-insensitively and may be internationalized
 	HandleOrDid string `protobuf:"bytes,1,opt,name=handle_or_did,json=handleOrDid,proto3" json:"handle_or_did,omitempty"`
+	// If set, an entry last checked longer ago than this is revalidated before responding,
+	// otherwise stale entries are returned as they are and revalidated in the background
+	MaxStaleness *durationpb.Duration `protobuf:"bytes,2,opt,name=max_staleness,json=maxStaleness,proto3" json:"max_staleness,omitempty"`
 }
 
 func (x *LookupRequest) Reset() {
@@ -69,15 +170,25 @@ func (x *LookupRequest) GetHandleOrDid() string {
 	return ""
 }
 
+func (x *LookupRequest) GetMaxStaleness() *durationpb.Duration {
+	if x != nil {
+		return x.MaxStaleness
+	}
+	return nil
+}
+
 type LookupResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Handle          string                 `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
-	Did             string                 `protobuf:"bytes,2,opt,name=did,proto3" json:"did,omitempty"`
-	IsValid         bool                   `protobuf:"varint,3,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
-	LastCheckedTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_checked_time,json=lastCheckedTime,proto3" json:"last_checked_time,omitempty"`
+	Handle           string                 `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	Did              string                 `protobuf:"bytes,2,opt,name=did,proto3" json:"did,omitempty"`
+	IsValid          bool                   `protobuf:"varint,3,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	LastCheckedTime  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_checked_time,json=lastCheckedTime,proto3" json:"last_checked_time,omitempty"`
+	ValidationStatus ValidationStatus       `protobuf:"varint,5,opt,name=validation_status,json=validationStatus,proto3,enum=bingo.v1.ValidationStatus" json:"validation_status,omitempty"`
+	// A short explanation of why validation failed, empty when the handle is valid
+	ValidationDetail string `protobuf:"bytes,6,opt,name=validation_detail,json=validationDetail,proto3" json:"validation_detail,omitempty"`
 }
 
 func (x *LookupResponse) Reset() {
@@ -140,6 +251,20 @@ func (x *LookupResponse) GetLastCheckedTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *LookupResponse) GetValidationStatus() ValidationStatus {
+	if x != nil {
+		return x.ValidationStatus
+	}
+	return ValidationStatus_VALIDATION_STATUS_UNSPECIFIED
+}
+
+func (x *LookupResponse) GetValidationDetail() string {
+	if x != nil {
+		return x.ValidationDetail
+	}
+	return ""
+}
+
 type BulkLookupRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -234,133 +359,1367 @@ func (x *BulkLookupResponse) GetResponses() []*LookupResponse {
 	return nil
 }
 
-var File_bingo_v1_bingo_proto protoreflect.FileDescriptor
+type GetHandleHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_bingo_v1_bingo_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x69, 0x6e, 0x67, 0x6f,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31,
-	0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76,
-	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3f,
-	0x0a, 0x0d, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x2e, 0x0a, 0x0d, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x6f, 0x72, 0x5f, 0x64, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0a, 0xba, 0x48, 0x07, 0x72, 0x05, 0x10, 0x01, 0x18,
-	0x80, 0x04, 0x52, 0x0b, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4f, 0x72, 0x44, 0x69, 0x64, 0x22,
-	0x9d, 0x01, 0x0a, 0x0e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x08,
-	0x69, 0x73, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
-	0x69, 0x73, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x46, 0x0a, 0x11, 0x6c, 0x61, 0x73, 0x74, 0x5f,
-	0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f,
-	0x6c, 0x61, 0x73, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22,
-	0x52, 0x0a, 0x11, 0x42, 0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x0f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x5f,
-	0x6f, 0x72, 0x5f, 0x64, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x42, 0x15, 0xba,
-	0x48, 0x12, 0x92, 0x01, 0x0f, 0x08, 0x01, 0x10, 0xa0, 0x9c, 0x01, 0x22, 0x07, 0x72, 0x05, 0x10,
-	0x01, 0x18, 0x80, 0x04, 0x52, 0x0d, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x4f, 0x72, 0x44,
-	0x69, 0x64, 0x73, 0x22, 0x4c, 0x0a, 0x12, 0x42, 0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75,
-	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x09, 0x72, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62,
-	0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x73, 0x32, 0x98, 0x01, 0x0a, 0x0c, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x12, 0x17, 0x2e, 0x62,
-	0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31,
-	0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x49, 0x0a, 0x0a, 0x42, 0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x12,
-	0x1b, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x4c,
-	0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62,
-	0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b,
-	0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x32, 0x5a, 0x30,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x72, 0x69, 0x63, 0x76,
-	0x6f, 0x6c, 0x70, 0x31, 0x32, 0x2f, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
-	0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x76, 0x31,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	// A DID or a handle, handles are matched case-insensitively and may be internationalized
+	HandleOrDid string `protobuf:"bytes,1,opt,name=handle_or_did,json=handleOrDid,proto3" json:"handle_or_did,omitempty"`
 }
 
-var (
-	file_bingo_v1_bingo_proto_rawDescOnce sync.Once
-	file_bingo_v1_bingo_proto_rawDescData = file_bingo_v1_bingo_proto_rawDesc
-)
+func (x *GetHandleHistoryRequest) Reset() {
+	*x = GetHandleHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_bingo_v1_bingo_proto_rawDescGZIP() []byte {
-	file_bingo_v1_bingo_proto_rawDescOnce.Do(func() {
-		file_bingo_v1_bingo_proto_rawDescData = protoimpl.X.CompressGZIP(file_bingo_v1_bingo_proto_rawDescData)
-	})
-	return file_bingo_v1_bingo_proto_rawDescData
+func (x *GetHandleHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_bingo_v1_bingo_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_bingo_v1_bingo_proto_goTypes = []interface{}{
-	(*LookupRequest)(nil),         // 0: bingo.v1.LookupRequest
-	(*LookupResponse)(nil),        // 1: bingo.v1.LookupResponse
-	(*BulkLookupRequest)(nil),     // 2: bingo.v1.BulkLookupRequest
-	(*BulkLookupResponse)(nil),    // 3: bingo.v1.BulkLookupResponse
-	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+func (*GetHandleHistoryRequest) ProtoMessage() {}
+
+func (x *GetHandleHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_bingo_v1_bingo_proto_depIdxs = []int32{
-	4, // 0: bingo.v1.LookupResponse.last_checked_time:type_name -> google.protobuf.Timestamp
-	1, // 1: bingo.v1.BulkLookupResponse.responses:type_name -> bingo.v1.LookupResponse
-	0, // 2: bingo.v1.BingoService.Lookup:input_type -> bingo.v1.LookupRequest
-	2, // 3: bingo.v1.BingoService.BulkLookup:input_type -> bingo.v1.BulkLookupRequest
-	1, // 4: bingo.v1.BingoService.Lookup:output_type -> bingo.v1.LookupResponse
-	3, // 5: bingo.v1.BingoService.BulkLookup:output_type -> bingo.v1.BulkLookupResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+
+// Deprecated: Use GetHandleHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHandleHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{4}
 }
 
-func init() { file_bingo_v1_bingo_proto_init() }
-func file_bingo_v1_bingo_proto_init() {
-	if File_bingo_v1_bingo_proto != nil {
-		return
+func (x *GetHandleHistoryRequest) GetHandleOrDid() string {
+	if x != nil {
+		return x.HandleOrDid
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_bingo_v1_bingo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LookupRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return ""
+}
+
+type HandleClaim struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Handle    string                 `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	Did       string                 `protobuf:"bytes,2,opt,name=did,proto3" json:"did,omitempty"`
+	Cid       string                 `protobuf:"bytes,3,opt,name=cid,proto3" json:"cid,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *HandleClaim) Reset() {
+	*x = HandleClaim{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandleClaim) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleClaim) ProtoMessage() {}
+
+func (x *HandleClaim) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_bingo_v1_bingo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LookupResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleClaim.ProtoReflect.Descriptor instead.
+func (*HandleClaim) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HandleClaim) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *HandleClaim) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+func (x *HandleClaim) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *HandleClaim) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetHandleHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Claims []*HandleClaim `protobuf:"bytes,1,rep,name=claims,proto3" json:"claims,omitempty"`
+}
+
+func (x *GetHandleHistoryResponse) Reset() {
+	*x = GetHandleHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetHandleHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHandleHistoryResponse) ProtoMessage() {}
+
+func (x *GetHandleHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_bingo_v1_bingo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BulkLookupRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHandleHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHandleHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetHandleHistoryResponse) GetClaims() []*HandleClaim {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+type ResolveDIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Did string `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+}
+
+func (x *ResolveDIDRequest) Reset() {
+	*x = ResolveDIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveDIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveDIDRequest) ProtoMessage() {}
+
+func (x *ResolveDIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_bingo_v1_bingo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BulkLookupResponse); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveDIDRequest.ProtoReflect.Descriptor instead.
+func (*ResolveDIDRequest) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResolveDIDRequest) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+type VerificationMethod struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                 string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type               string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Controller         string `protobuf:"bytes,3,opt,name=controller,proto3" json:"controller,omitempty"`
+	PublicKeyMultibase string `protobuf:"bytes,4,opt,name=public_key_multibase,json=publicKeyMultibase,proto3" json:"public_key_multibase,omitempty"`
+}
+
+func (x *VerificationMethod) Reset() {
+	*x = VerificationMethod{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerificationMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerificationMethod) ProtoMessage() {}
+
+func (x *VerificationMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerificationMethod.ProtoReflect.Descriptor instead.
+func (*VerificationMethod) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VerificationMethod) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VerificationMethod) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *VerificationMethod) GetController() string {
+	if x != nil {
+		return x.Controller
+	}
+	return ""
+}
+
+func (x *VerificationMethod) GetPublicKeyMultibase() string {
+	if x != nil {
+		return x.PublicKeyMultibase
+	}
+	return ""
+}
+
+type Service struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type            string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	ServiceEndpoint string `protobuf:"bytes,3,opt,name=service_endpoint,json=serviceEndpoint,proto3" json:"service_endpoint,omitempty"`
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Service) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Service) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Service) GetServiceEndpoint() string {
+	if x != nil {
+		return x.ServiceEndpoint
+	}
+	return ""
+}
+
+type DIDDocument struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Context            []string              `protobuf:"bytes,1,rep,name=context,proto3" json:"context,omitempty"`
+	Id                 string                `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	AlsoKnownAs        []string              `protobuf:"bytes,3,rep,name=also_known_as,json=alsoKnownAs,proto3" json:"also_known_as,omitempty"`
+	VerificationMethod []*VerificationMethod `protobuf:"bytes,4,rep,name=verification_method,json=verificationMethod,proto3" json:"verification_method,omitempty"`
+	Service            []*Service            `protobuf:"bytes,5,rep,name=service,proto3" json:"service,omitempty"`
+}
+
+func (x *DIDDocument) Reset() {
+	*x = DIDDocument{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DIDDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DIDDocument) ProtoMessage() {}
+
+func (x *DIDDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DIDDocument.ProtoReflect.Descriptor instead.
+func (*DIDDocument) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DIDDocument) GetContext() []string {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *DIDDocument) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DIDDocument) GetAlsoKnownAs() []string {
+	if x != nil {
+		return x.AlsoKnownAs
+	}
+	return nil
+}
+
+func (x *DIDDocument) GetVerificationMethod() []*VerificationMethod {
+	if x != nil {
+		return x.VerificationMethod
+	}
+	return nil
+}
+
+func (x *DIDDocument) GetService() []*Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+type ResolveDIDResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Document     *DIDDocument           `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	RotationKeys []string               `protobuf:"bytes,2,rep,name=rotation_keys,json=rotationKeys,proto3" json:"rotation_keys,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ChainInvalid bool                   `protobuf:"varint,4,opt,name=chain_invalid,json=chainInvalid,proto3" json:"chain_invalid,omitempty"`
+	ChainError   string                 `protobuf:"bytes,5,opt,name=chain_error,json=chainError,proto3" json:"chain_error,omitempty"`
+}
+
+func (x *ResolveDIDResponse) Reset() {
+	*x = ResolveDIDResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveDIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveDIDResponse) ProtoMessage() {}
+
+func (x *ResolveDIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveDIDResponse.ProtoReflect.Descriptor instead.
+func (*ResolveDIDResponse) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ResolveDIDResponse) GetDocument() *DIDDocument {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *ResolveDIDResponse) GetRotationKeys() []string {
+	if x != nil {
+		return x.RotationKeys
+	}
+	return nil
+}
+
+func (x *ResolveDIDResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ResolveDIDResponse) GetChainInvalid() bool {
+	if x != nil {
+		return x.ChainInvalid
+	}
+	return false
+}
+
+func (x *ResolveDIDResponse) GetChainError() string {
+	if x != nil {
+		return x.ChainError
+	}
+	return ""
+}
+
+type RevalidateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HandlesOrDids []string `protobuf:"bytes,1,rep,name=handles_or_dids,json=handlesOrDids,proto3" json:"handles_or_dids,omitempty"`
+}
+
+func (x *RevalidateRequest) Reset() {
+	*x = RevalidateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevalidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevalidateRequest) ProtoMessage() {}
+
+func (x *RevalidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevalidateRequest.ProtoReflect.Descriptor instead.
+func (*RevalidateRequest) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RevalidateRequest) GetHandlesOrDids() []string {
+	if x != nil {
+		return x.HandlesOrDids
+	}
+	return nil
+}
+
+type RevalidationResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The DID that was revalidated, or the requested identifier if it couldn't be resolved to one
+	HandleOrDid string `protobuf:"bytes,1,opt,name=handle_or_did,json=handleOrDid,proto3" json:"handle_or_did,omitempty"`
+	// Set once the identifier has been revalidated successfully
+	Entry *LookupResponse `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	// Set if the identifier couldn't be revalidated, e.g. because it isn't tracked
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *RevalidationResult) Reset() {
+	*x = RevalidationResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevalidationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevalidationResult) ProtoMessage() {}
+
+func (x *RevalidationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevalidationResult.ProtoReflect.Descriptor instead.
+func (*RevalidationResult) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevalidationResult) GetHandleOrDid() string {
+	if x != nil {
+		return x.HandleOrDid
+	}
+	return ""
+}
+
+func (x *RevalidationResult) GetEntry() *LookupResponse {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *RevalidationResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RevalidationJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// True once every identifier has a result, otherwise poll GetRevalidationJob with the ID
+	Done bool `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`
+	// Results for the identifiers revalidated so far
+	Results []*RevalidationResult `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *RevalidationJob) Reset() {
+	*x = RevalidationJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevalidationJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevalidationJob) ProtoMessage() {}
+
+func (x *RevalidationJob) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevalidationJob.ProtoReflect.Descriptor instead.
+func (*RevalidationJob) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RevalidationJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevalidationJob) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *RevalidationJob) GetResults() []*RevalidationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type RevalidateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job *RevalidationJob `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *RevalidateResponse) Reset() {
+	*x = RevalidateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevalidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevalidateResponse) ProtoMessage() {}
+
+func (x *RevalidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevalidateResponse.ProtoReflect.Descriptor instead.
+func (*RevalidateResponse) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RevalidateResponse) GetJob() *RevalidationJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type GetRevalidationJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetRevalidationJobRequest) Reset() {
+	*x = GetRevalidationJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRevalidationJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRevalidationJobRequest) ProtoMessage() {}
+
+func (x *GetRevalidationJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRevalidationJobRequest.ProtoReflect.Descriptor instead.
+func (*GetRevalidationJobRequest) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetRevalidationJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetRevalidationJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job *RevalidationJob `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *GetRevalidationJobResponse) Reset() {
+	*x = GetRevalidationJobResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bingo_v1_bingo_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRevalidationJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRevalidationJobResponse) ProtoMessage() {}
+
+func (x *GetRevalidationJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bingo_v1_bingo_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRevalidationJobResponse.ProtoReflect.Descriptor instead.
+func (*GetRevalidationJobResponse) Descriptor() ([]byte, []int) {
+	return file_bingo_v1_bingo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetRevalidationJobResponse) GetJob() *RevalidationJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+var File_bingo_v1_bingo_proto protoreflect.FileDescriptor
+
+var file_bingo_v1_bingo_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x69, 0x6e, 0x67, 0x6f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31,
+	0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa3,
+	0x02, 0x0a, 0x0d, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0xc7, 0x01, 0x0a, 0x0d, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x6f, 0x72, 0x5f, 0x64,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0xa2, 0x01, 0xba, 0x48, 0x9e, 0x01, 0x72,
+	0x9b, 0x01, 0x10, 0x01, 0x18, 0x80, 0x04, 0x32, 0x93, 0x01, 0x5e, 0x28, 0x64, 0x69, 0x64, 0x3a,
+	0x5b, 0x61, 0x2d, 0x7a, 0x5d, 0x2b, 0x3a, 0x5b, 0x61, 0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d,
+	0x39, 0x2e, 0x5f, 0x3a, 0x25, 0x2d, 0x5d, 0x2a, 0x5b, 0x61, 0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x30,
+	0x2d, 0x39, 0x2e, 0x5f, 0x2d, 0x5d, 0x7c, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70,
+	0x7b, 0x4e, 0x7d, 0x5d, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d,
+	0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70,
+	0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d, 0x29, 0x3f, 0x5c, 0x2e, 0x29, 0x2b, 0x5c,
+	0x70, 0x7b, 0x4c, 0x7d, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d,
+	0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70,
+	0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d, 0x29, 0x3f, 0x29, 0x24, 0x52, 0x0b, 0x68,
+	0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4f, 0x72, 0x44, 0x69, 0x64, 0x12, 0x48, 0x0a, 0x0d, 0x6d, 0x61,
+	0x78, 0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0xba, 0x48,
+	0x05, 0xaa, 0x01, 0x02, 0x32, 0x00, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x53, 0x74, 0x61, 0x6c, 0x65,
+	0x6e, 0x65, 0x73, 0x73, 0x22, 0x93, 0x02, 0x0a, 0x0e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x64, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x69,
+	0x64, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x46, 0x0a, 0x11,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x47, 0x0a, 0x11, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1a, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x10, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a,
+	0x11, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x22, 0xee, 0x01, 0x0a, 0x11, 0x42,
+	0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0xd8, 0x01, 0x0a, 0x0f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x5f, 0x6f, 0x72, 0x5f,
+	0x64, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x42, 0xaf, 0x01, 0xba, 0x48, 0xab,
+	0x01, 0x92, 0x01, 0xa7, 0x01, 0x08, 0x01, 0x10, 0xa0, 0x9c, 0x01, 0x22, 0x9e, 0x01, 0x72, 0x9b,
+	0x01, 0x10, 0x01, 0x18, 0x80, 0x04, 0x32, 0x93, 0x01, 0x5e, 0x28, 0x64, 0x69, 0x64, 0x3a, 0x5b,
+	0x61, 0x2d, 0x7a, 0x5d, 0x2b, 0x3a, 0x5b, 0x61, 0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d, 0x39,
+	0x2e, 0x5f, 0x3a, 0x25, 0x2d, 0x5d, 0x2a, 0x5b, 0x61, 0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d,
+	0x39, 0x2e, 0x5f, 0x2d, 0x5d, 0x7c, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b,
+	0x4e, 0x7d, 0x5d, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c,
+	0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b,
+	0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d, 0x29, 0x3f, 0x5c, 0x2e, 0x29, 0x2b, 0x5c, 0x70,
+	0x7b, 0x4c, 0x7d, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c,
+	0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b,
+	0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d, 0x29, 0x3f, 0x29, 0x24, 0x52, 0x0d, 0x68, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x73, 0x4f, 0x72, 0x44, 0x69, 0x64, 0x73, 0x22, 0x4c, 0x0a, 0x12, 0x42,
+	0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x36, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22, 0xe3, 0x01, 0x0a, 0x17, 0x47, 0x65,
+	0x74, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0xc7, 0x01, 0x0a, 0x0d, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x5f, 0x6f, 0x72, 0x5f, 0x64, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0xa2, 0x01,
+	0xba, 0x48, 0x9e, 0x01, 0x72, 0x9b, 0x01, 0x10, 0x01, 0x18, 0x80, 0x04, 0x32, 0x93, 0x01, 0x5e,
+	0x28, 0x64, 0x69, 0x64, 0x3a, 0x5b, 0x61, 0x2d, 0x7a, 0x5d, 0x2b, 0x3a, 0x5b, 0x61, 0x2d, 0x7a,
+	0x41, 0x2d, 0x5a, 0x30, 0x2d, 0x39, 0x2e, 0x5f, 0x3a, 0x25, 0x2d, 0x5d, 0x2a, 0x5b, 0x61, 0x2d,
+	0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d, 0x39, 0x2e, 0x5f, 0x2d, 0x5d, 0x7c, 0x28, 0x5b, 0x5c, 0x70,
+	0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5d, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d,
+	0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b, 0x5c, 0x70,
+	0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d, 0x29, 0x3f,
+	0x5c, 0x2e, 0x29, 0x2b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x28, 0x5b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d,
+	0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b, 0x5c, 0x70,
+	0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d, 0x29, 0x3f,
+	0x29, 0x24, 0x52, 0x0b, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4f, 0x72, 0x44, 0x69, 0x64, 0x22,
+	0x84, 0x01, 0x0a, 0x0b, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x12,
+	0x16, 0x0a, 0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x49, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x48, 0x61, 0x6e,
+	0x64, 0x6c, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2d, 0x0a, 0x06, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x52, 0x06, 0x63, 0x6c, 0x61, 0x69, 0x6d,
+	0x73, 0x22, 0x5f, 0x0a, 0x11, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x44, 0x49, 0x44, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4a, 0x0a, 0x03, 0x64, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x38, 0xba, 0x48, 0x35, 0x72, 0x33, 0x10, 0x01, 0x18, 0x80, 0x04, 0x32,
+	0x2c, 0x5e, 0x64, 0x69, 0x64, 0x3a, 0x5b, 0x61, 0x2d, 0x7a, 0x5d, 0x2b, 0x3a, 0x5b, 0x61, 0x2d,
+	0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d, 0x39, 0x2e, 0x5f, 0x3a, 0x25, 0x2d, 0x5d, 0x2a, 0x5b, 0x61,
+	0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d, 0x39, 0x2e, 0x5f, 0x2d, 0x5d, 0x24, 0x52, 0x03, 0x64,
+	0x69, 0x64, 0x22, 0x8a, 0x01, 0x0a, 0x12, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x30, 0x0a,
+	0x14, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x6d, 0x75, 0x6c, 0x74,
+	0x69, 0x62, 0x61, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x62, 0x61, 0x73, 0x65, 0x22,
+	0x58, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x29,
+	0x0a, 0x10, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0xd7, 0x01, 0x0a, 0x0b, 0x44, 0x49,
+	0x44, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x61, 0x6c, 0x73, 0x6f, 0x5f, 0x6b, 0x6e, 0x6f, 0x77,
+	0x6e, 0x5f, 0x61, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x73, 0x6f,
+	0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x41, 0x73, 0x12, 0x4d, 0x0a, 0x13, 0x76, 0x65, 0x72, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x52, 0x12, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x2b, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x22, 0xed, 0x01, 0x0a, 0x12, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x44,
+	0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62,
+	0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x49, 0x44, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x0a,
+	0x0d, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65,
+	0x79, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x23, 0x0a,
+	0x0d, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x22, 0xed, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0xd7, 0x01, 0x0a, 0x0f, 0x68, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x73, 0x5f, 0x6f, 0x72, 0x5f, 0x64, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x42, 0xae, 0x01, 0xba, 0x48, 0xaa, 0x01, 0x92, 0x01, 0xa6, 0x01, 0x08, 0x01,
+	0x10, 0xe8, 0x07, 0x22, 0x9e, 0x01, 0x72, 0x9b, 0x01, 0x10, 0x01, 0x18, 0x80, 0x04, 0x32, 0x93,
+	0x01, 0x5e, 0x28, 0x64, 0x69, 0x64, 0x3a, 0x5b, 0x61, 0x2d, 0x7a, 0x5d, 0x2b, 0x3a, 0x5b, 0x61,
+	0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d, 0x39, 0x2e, 0x5f, 0x3a, 0x25, 0x2d, 0x5d, 0x2a, 0x5b,
+	0x61, 0x2d, 0x7a, 0x41, 0x2d, 0x5a, 0x30, 0x2d, 0x39, 0x2e, 0x5f, 0x2d, 0x5d, 0x7c, 0x28, 0x5b,
+	0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5d, 0x28, 0x5b, 0x5c, 0x70, 0x7b,
+	0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b,
+	0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d,
+	0x29, 0x3f, 0x5c, 0x2e, 0x29, 0x2b, 0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x28, 0x5b, 0x5c, 0x70, 0x7b,
+	0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x2d, 0x5d, 0x2a, 0x5b,
+	0x5c, 0x70, 0x7b, 0x4c, 0x7d, 0x5c, 0x70, 0x7b, 0x4e, 0x7d, 0x5c, 0x70, 0x7b, 0x4d, 0x7d, 0x5d,
+	0x29, 0x3f, 0x29, 0x24, 0x52, 0x0d, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x4f, 0x72, 0x44,
+	0x69, 0x64, 0x73, 0x22, 0x7e, 0x0a, 0x12, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x68, 0x61, 0x6e,
+	0x64, 0x6c, 0x65, 0x5f, 0x6f, 0x72, 0x5f, 0x64, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4f, 0x72, 0x44, 0x69, 0x64, 0x12, 0x2e, 0x0a,
+	0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62,
+	0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x22, 0x6d, 0x0a, 0x0f, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62, 0x69,
+	0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x22, 0x41, 0x0a, 0x12, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62,
+	0x52, 0x03, 0x6a, 0x6f, 0x62, 0x22, 0x36, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x52, 0x65, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x09,
+	0xba, 0x48, 0x06, 0x72, 0x04, 0x10, 0x01, 0x18, 0x40, 0x52, 0x02, 0x69, 0x64, 0x22, 0x49, 0x0a,
+	0x1a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x03, 0x6a,
+	0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x4a, 0x6f, 0x62, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x2a, 0xf5, 0x03, 0x0a, 0x10, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a,
+	0x1d, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x1b, 0x0a, 0x17, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x01, 0x12, 0x1f, 0x0a,
+	0x1b, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x55, 0x4e, 0x43, 0x4c, 0x41, 0x49, 0x4d, 0x45, 0x44, 0x10, 0x02, 0x12, 0x24,
+	0x0a, 0x20, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x44, 0x4f, 0x43, 0x55, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x10, 0x03, 0x12, 0x1e, 0x0a, 0x1a, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4e, 0x58, 0x44, 0x4f, 0x4d, 0x41,
+	0x49, 0x4e, 0x10, 0x04, 0x12, 0x22, 0x0a, 0x1e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x54, 0x58, 0x54, 0x5f, 0x4d, 0x49,
+	0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x05, 0x12, 0x1f, 0x0a, 0x1b, 0x56, 0x41, 0x4c, 0x49,
+	0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x44, 0x4e,
+	0x53, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x06, 0x12, 0x21, 0x0a, 0x1d, 0x56, 0x41, 0x4c,
+	0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x48,
+	0x54, 0x54, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x10, 0x07, 0x12, 0x1f, 0x0a, 0x1b,
+	0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x54, 0x4c, 0x53, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x08, 0x12, 0x1d, 0x0a,
+	0x19, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x09, 0x12, 0x26, 0x0a, 0x22,
+	0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x10, 0x0a, 0x12, 0x23, 0x0a, 0x1f, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x42, 0x4f, 0x44, 0x59, 0x5f, 0x4d,
+	0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x0b, 0x12, 0x24, 0x0a, 0x20, 0x56, 0x41, 0x4c,
+	0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x42,
+	0x4f, 0x44, 0x59, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x41, 0x52, 0x47, 0x45, 0x10, 0x0c, 0x12,
+	0x1f, 0x0a, 0x1b, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x4e, 0x4f, 0x5f, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x10, 0x0d,
+	0x32, 0xee, 0x03, 0x0a, 0x0c, 0x42, 0x69, 0x6e, 0x67, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x3d, 0x0a, 0x06, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x12, 0x17, 0x2e, 0x62, 0x69,
+	0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x49, 0x0a, 0x0a, 0x42, 0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x12, 0x1b,
+	0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x4c, 0x6f,
+	0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x69,
+	0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x10, 0x47,
+	0x65, 0x74, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x21, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0a, 0x52, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x44, 0x49, 0x44, 0x12, 0x1b, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x44, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x44, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0a, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x12, 0x1b, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x61,
+	0x0a, 0x12, 0x47, 0x65, 0x74, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x23, 0x2e, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4a,
+	0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x62, 0x69, 0x6e, 0x67,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x65, 0x72, 0x69, 0x63, 0x76, 0x6f, 0x6c, 0x70, 0x31, 0x32, 0x2f, 0x62, 0x69, 0x6e, 0x67, 0x6f,
+	0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x62, 0x69, 0x6e, 0x67, 0x6f, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x69,
+	0x6e, 0x67, 0x6f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_bingo_v1_bingo_proto_rawDescOnce sync.Once
+	file_bingo_v1_bingo_proto_rawDescData = file_bingo_v1_bingo_proto_rawDesc
+)
+
+func file_bingo_v1_bingo_proto_rawDescGZIP() []byte {
+	file_bingo_v1_bingo_proto_rawDescOnce.Do(func() {
+		file_bingo_v1_bingo_proto_rawDescData = protoimpl.X.CompressGZIP(file_bingo_v1_bingo_proto_rawDescData)
+	})
+	return file_bingo_v1_bingo_proto_rawDescData
+}
+
+var file_bingo_v1_bingo_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_bingo_v1_bingo_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_bingo_v1_bingo_proto_goTypes = []interface{}{
+	(ValidationStatus)(0),              // 0: bingo.v1.ValidationStatus
+	(*LookupRequest)(nil),              // 1: bingo.v1.LookupRequest
+	(*LookupResponse)(nil),             // 2: bingo.v1.LookupResponse
+	(*BulkLookupRequest)(nil),          // 3: bingo.v1.BulkLookupRequest
+	(*BulkLookupResponse)(nil),         // 4: bingo.v1.BulkLookupResponse
+	(*GetHandleHistoryRequest)(nil),    // 5: bingo.v1.GetHandleHistoryRequest
+	(*HandleClaim)(nil),                // 6: bingo.v1.HandleClaim
+	(*GetHandleHistoryResponse)(nil),   // 7: bingo.v1.GetHandleHistoryResponse
+	(*ResolveDIDRequest)(nil),          // 8: bingo.v1.ResolveDIDRequest
+	(*VerificationMethod)(nil),         // 9: bingo.v1.VerificationMethod
+	(*Service)(nil),                    // 10: bingo.v1.Service
+	(*DIDDocument)(nil),                // 11: bingo.v1.DIDDocument
+	(*ResolveDIDResponse)(nil),         // 12: bingo.v1.ResolveDIDResponse
+	(*RevalidateRequest)(nil),          // 13: bingo.v1.RevalidateRequest
+	(*RevalidationResult)(nil),         // 14: bingo.v1.RevalidationResult
+	(*RevalidationJob)(nil),            // 15: bingo.v1.RevalidationJob
+	(*RevalidateResponse)(nil),         // 16: bingo.v1.RevalidateResponse
+	(*GetRevalidationJobRequest)(nil),  // 17: bingo.v1.GetRevalidationJobRequest
+	(*GetRevalidationJobResponse)(nil), // 18: bingo.v1.GetRevalidationJobResponse
+	(*durationpb.Duration)(nil),        // 19: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),      // 20: google.protobuf.Timestamp
+}
+var file_bingo_v1_bingo_proto_depIdxs = []int32{
+	19, // 0: bingo.v1.LookupRequest.max_staleness:type_name -> google.protobuf.Duration
+	20, // 1: bingo.v1.LookupResponse.last_checked_time:type_name -> google.protobuf.Timestamp
+	0,  // 2: bingo.v1.LookupResponse.validation_status:type_name -> bingo.v1.ValidationStatus
+	2,  // 3: bingo.v1.BulkLookupResponse.responses:type_name -> bingo.v1.LookupResponse
+	20, // 4: bingo.v1.HandleClaim.created_at:type_name -> google.protobuf.Timestamp
+	6,  // 5: bingo.v1.GetHandleHistoryResponse.claims:type_name -> bingo.v1.HandleClaim
+	9,  // 6: bingo.v1.DIDDocument.verification_method:type_name -> bingo.v1.VerificationMethod
+	10, // 7: bingo.v1.DIDDocument.service:type_name -> bingo.v1.Service
+	11, // 8: bingo.v1.ResolveDIDResponse.document:type_name -> bingo.v1.DIDDocument
+	20, // 9: bingo.v1.ResolveDIDResponse.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 10: bingo.v1.RevalidationResult.entry:type_name -> bingo.v1.LookupResponse
+	14, // 11: bingo.v1.RevalidationJob.results:type_name -> bingo.v1.RevalidationResult
+	15, // 12: bingo.v1.RevalidateResponse.job:type_name -> bingo.v1.RevalidationJob
+	15, // 13: bingo.v1.GetRevalidationJobResponse.job:type_name -> bingo.v1.RevalidationJob
+	1,  // 14: bingo.v1.BingoService.Lookup:input_type -> bingo.v1.LookupRequest
+	3,  // 15: bingo.v1.BingoService.BulkLookup:input_type -> bingo.v1.BulkLookupRequest
+	5,  // 16: bingo.v1.BingoService.GetHandleHistory:input_type -> bingo.v1.GetHandleHistoryRequest
+	8,  // 17: bingo.v1.BingoService.ResolveDID:input_type -> bingo.v1.ResolveDIDRequest
+	13, // 18: bingo.v1.BingoService.Revalidate:input_type -> bingo.v1.RevalidateRequest
+	17, // 19: bingo.v1.BingoService.GetRevalidationJob:input_type -> bingo.v1.GetRevalidationJobRequest
+	2,  // 20: bingo.v1.BingoService.Lookup:output_type -> bingo.v1.LookupResponse
+	4,  // 21: bingo.v1.BingoService.BulkLookup:output_type -> bingo.v1.BulkLookupResponse
+	7,  // 22: bingo.v1.BingoService.GetHandleHistory:output_type -> bingo.v1.GetHandleHistoryResponse
+	12, // 23: bingo.v1.BingoService.ResolveDID:output_type -> bingo.v1.ResolveDIDResponse
+	16, // 24: bingo.v1.BingoService.Revalidate:output_type -> bingo.v1.RevalidateResponse
+	18, // 25: bingo.v1.BingoService.GetRevalidationJob:output_type -> bingo.v1.GetRevalidationJobResponse
+	20, // [20:26] is the sub-list for method output_type
+	14, // [14:20] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_bingo_v1_bingo_proto_init() }
+func file_bingo_v1_bingo_proto_init() {
+	if File_bingo_v1_bingo_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_bingo_v1_bingo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BulkLookupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BulkLookupResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetHandleHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandleClaim); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetHandleHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveDIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerificationMethod); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Service); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DIDDocument); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveDIDResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevalidateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevalidationResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevalidationJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevalidateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRevalidationJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bingo_v1_bingo_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRevalidationJobResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -377,13 +1736,14 @@ func file_bingo_v1_bingo_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_bingo_v1_bingo_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_bingo_v1_bingo_proto_goTypes,
 		DependencyIndexes: file_bingo_v1_bingo_proto_depIdxs,
+		EnumInfos:         file_bingo_v1_bingo_proto_enumTypes,
 		MessageInfos:      file_bingo_v1_bingo_proto_msgTypes,
 	}.Build()
 	File_bingo_v1_bingo_proto = out.File
@@ -37,12 +37,26 @@ const (
 	BingoServiceLookupProcedure = "/bingo.v1.BingoService/Lookup"
 	// BingoServiceBulkLookupProcedure is the fully-qualified name of the BingoService's BulkLookup RPC.
 	BingoServiceBulkLookupProcedure = "/bingo.v1.BingoService/BulkLookup"
+	// BingoServiceGetHandleHistoryProcedure is the fully-qualified name of the BingoService's
+	// GetHandleHistory RPC.
+	BingoServiceGetHandleHistoryProcedure = "/bingo.v1.BingoService/GetHandleHistory"
+	// BingoServiceResolveDIDProcedure is the fully-qualified name of the BingoService's ResolveDID RPC.
+	BingoServiceResolveDIDProcedure = "/bingo.v1.BingoService/ResolveDID"
+	// BingoServiceRevalidateProcedure is the fully-qualified name of the BingoService's Revalidate RPC.
+	BingoServiceRevalidateProcedure = "/bingo.v1.BingoService/Revalidate"
+	// BingoServiceGetRevalidationJobProcedure is the fully-qualified name of the BingoService's
+	// GetRevalidationJob RPC.
+	BingoServiceGetRevalidationJobProcedure = "/bingo.v1.BingoService/GetRevalidationJob"
 )
 
 // BingoServiceClient is a client for the bingo.v1.BingoService service.
 type BingoServiceClient interface {
 	Lookup(context.Context, *connect.Request[v1.LookupRequest]) (*connect.Response[v1.LookupResponse], error)
 	BulkLookup(context.Context, *connect.Request[v1.BulkLookupRequest]) (*connect.Response[v1.BulkLookupResponse], error)
+	GetHandleHistory(context.Context, *connect.Request[v1.GetHandleHistoryRequest]) (*connect.Response[v1.GetHandleHistoryResponse], error)
+	ResolveDID(context.Context, *connect.Request[v1.ResolveDIDRequest]) (*connect.Response[v1.ResolveDIDResponse], error)
+	Revalidate(context.Context, *connect.Request[v1.RevalidateRequest]) (*connect.Response[v1.RevalidateResponse], error)
+	GetRevalidationJob(context.Context, *connect.Request[v1.GetRevalidationJobRequest]) (*connect.Response[v1.GetRevalidationJobResponse], error)
 }
 
 // NewBingoServiceClient constructs a client for the bingo.v1.BingoService service. By default, it
@@ -65,13 +79,37 @@ func NewBingoServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			baseURL+BingoServiceBulkLookupProcedure,
 			opts...,
 		),
+		getHandleHistory: connect.NewClient[v1.GetHandleHistoryRequest, v1.GetHandleHistoryResponse](
+			httpClient,
+			baseURL+BingoServiceGetHandleHistoryProcedure,
+			opts...,
+		),
+		resolveDID: connect.NewClient[v1.ResolveDIDRequest, v1.ResolveDIDResponse](
+			httpClient,
+			baseURL+BingoServiceResolveDIDProcedure,
+			opts...,
+		),
+		revalidate: connect.NewClient[v1.RevalidateRequest, v1.RevalidateResponse](
+			httpClient,
+			baseURL+BingoServiceRevalidateProcedure,
+			opts...,
+		),
+		getRevalidationJob: connect.NewClient[v1.GetRevalidationJobRequest, v1.GetRevalidationJobResponse](
+			httpClient,
+			baseURL+BingoServiceGetRevalidationJobProcedure,
+			opts...,
+		),
 	}
 }
 
 // bingoServiceClient implements BingoServiceClient.
 type bingoServiceClient struct {
-	lookup     *connect.Client[v1.LookupRequest, v1.LookupResponse]
-	bulkLookup *connect.Client[v1.BulkLookupRequest, v1.BulkLookupResponse]
+	lookup             *connect.Client[v1.LookupRequest, v1.LookupResponse]
+	bulkLookup         *connect.Client[v1.BulkLookupRequest, v1.BulkLookupResponse]
+	getHandleHistory   *connect.Client[v1.GetHandleHistoryRequest, v1.GetHandleHistoryResponse]
+	resolveDID         *connect.Client[v1.ResolveDIDRequest, v1.ResolveDIDResponse]
+	revalidate         *connect.Client[v1.RevalidateRequest, v1.RevalidateResponse]
+	getRevalidationJob *connect.Client[v1.GetRevalidationJobRequest, v1.GetRevalidationJobResponse]
 }
 
 // Lookup calls bingo.v1.BingoService.Lookup.
@@ -84,10 +122,34 @@ func (c *bingoServiceClient) BulkLookup(ctx context.Context, req *connect.Reques
 	return c.bulkLookup.CallUnary(ctx, req)
 }
 
+// GetHandleHistory calls bingo.v1.BingoService.GetHandleHistory.
+func (c *bingoServiceClient) GetHandleHistory(ctx context.Context, req *connect.Request[v1.GetHandleHistoryRequest]) (*connect.Response[v1.GetHandleHistoryResponse], error) {
+	return c.getHandleHistory.CallUnary(ctx, req)
+}
+
+// ResolveDID calls bingo.v1.BingoService.ResolveDID.
+func (c *bingoServiceClient) ResolveDID(ctx context.Context, req *connect.Request[v1.ResolveDIDRequest]) (*connect.Response[v1.ResolveDIDResponse], error) {
+	return c.resolveDID.CallUnary(ctx, req)
+}
+
+// Revalidate calls bingo.v1.BingoService.Revalidate.
+func (c *bingoServiceClient) Revalidate(ctx context.Context, req *connect.Request[v1.RevalidateRequest]) (*connect.Response[v1.RevalidateResponse], error) {
+	return c.revalidate.CallUnary(ctx, req)
+}
+
+// GetRevalidationJob calls bingo.v1.BingoService.GetRevalidationJob.
+func (c *bingoServiceClient) GetRevalidationJob(ctx context.Context, req *connect.Request[v1.GetRevalidationJobRequest]) (*connect.Response[v1.GetRevalidationJobResponse], error) {
+	return c.getRevalidationJob.CallUnary(ctx, req)
+}
+
 // BingoServiceHandler is an implementation of the bingo.v1.BingoService service.
 type BingoServiceHandler interface {
 	Lookup(context.Context, *connect.Request[v1.LookupRequest]) (*connect.Response[v1.LookupResponse], error)
 	BulkLookup(context.Context, *connect.Request[v1.BulkLookupRequest]) (*connect.Response[v1.BulkLookupResponse], error)
+	GetHandleHistory(context.Context, *connect.Request[v1.GetHandleHistoryRequest]) (*connect.Response[v1.GetHandleHistoryResponse], error)
+	ResolveDID(context.Context, *connect.Request[v1.ResolveDIDRequest]) (*connect.Response[v1.ResolveDIDResponse], error)
+	Revalidate(context.Context, *connect.Request[v1.RevalidateRequest]) (*connect.Response[v1.RevalidateResponse], error)
+	GetRevalidationJob(context.Context, *connect.Request[v1.GetRevalidationJobRequest]) (*connect.Response[v1.GetRevalidationJobResponse], error)
 }
 
 // NewBingoServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -106,12 +168,40 @@ func NewBingoServiceHandler(svc BingoServiceHandler, opts ...connect.HandlerOpti
 		svc.BulkLookup,
 		opts...,
 	)
+	bingoServiceGetHandleHistoryHandler := connect.NewUnaryHandler(
+		BingoServiceGetHandleHistoryProcedure,
+		svc.GetHandleHistory,
+		opts...,
+	)
+	bingoServiceResolveDIDHandler := connect.NewUnaryHandler(
+		BingoServiceResolveDIDProcedure,
+		svc.ResolveDID,
+		opts...,
+	)
+	bingoServiceRevalidateHandler := connect.NewUnaryHandler(
+		BingoServiceRevalidateProcedure,
+		svc.Revalidate,
+		opts...,
+	)
+	bingoServiceGetRevalidationJobHandler := connect.NewUnaryHandler(
+		BingoServiceGetRevalidationJobProcedure,
+		svc.GetRevalidationJob,
+		opts...,
+	)
 	return "/bingo.v1.BingoService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case BingoServiceLookupProcedure:
 			bingoServiceLookupHandler.ServeHTTP(w, r)
 		case BingoServiceBulkLookupProcedure:
 			bingoServiceBulkLookupHandler.ServeHTTP(w, r)
+		case BingoServiceGetHandleHistoryProcedure:
+			bingoServiceGetHandleHistoryHandler.ServeHTTP(w, r)
+		case BingoServiceResolveDIDProcedure:
+			bingoServiceResolveDIDHandler.ServeHTTP(w, r)
+		case BingoServiceRevalidateProcedure:
+			bingoServiceRevalidateHandler.ServeHTTP(w, r)
+		case BingoServiceGetRevalidationJobProcedure:
+			bingoServiceGetRevalidationJobHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -128,3 +218,19 @@ func (UnimplementedBingoServiceHandler) Lookup(context.Context, *connect.Request
 func (UnimplementedBingoServiceHandler) BulkLookup(context.Context, *connect.Request[v1.BulkLookupRequest]) (*connect.Response[v1.BulkLookupResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bingo.v1.BingoService.BulkLookup is not implemented"))
 }
+
+func (UnimplementedBingoServiceHandler) GetHandleHistory(context.Context, *connect.Request[v1.GetHandleHistoryRequest]) (*connect.Response[v1.GetHandleHistoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bingo.v1.BingoService.GetHandleHistory is not implemented"))
+}
+
+func (UnimplementedBingoServiceHandler) ResolveDID(context.Context, *connect.Request[v1.ResolveDIDRequest]) (*connect.Response[v1.ResolveDIDResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bingo.v1.BingoService.ResolveDID is not implemented"))
+}
+
+func (UnimplementedBingoServiceHandler) Revalidate(context.Context, *connect.Request[v1.RevalidateRequest]) (*connect.Response[v1.RevalidateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bingo.v1.BingoService.Revalidate is not implemented"))
+}
+
+func (UnimplementedBingoServiceHandler) GetRevalidationJob(context.Context, *connect.Request[v1.GetRevalidationJobRequest]) (*connect.Response[v1.GetRevalidationJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bingo.v1.BingoService.GetRevalidationJob is not implemented"))
+}
@@ -0,0 +1,132 @@
+package didweb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ericvolp12/bingo/pkg/syntax"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Document is the subset of a did:web DID document that bingo cares about
+type Document struct {
+	ID                 string               `json:"id"`
+	AlsoKnownAs        []string             `json:"alsoKnownAs"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	Service            []Service            `json:"service"`
+}
+
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+type Service struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// maxDocumentSize caps how much of a DID document we're willing to read
+const maxDocumentSize = 1024 * 1024
+
+var ErrInvalidDID = errors.New("didweb: invalid did:web")
+
+var tracer = otel.Tracer("bingo/didweb")
+
+// IsDIDWeb reports whether the DID uses the did:web method
+func IsDIDWeb(did string) bool {
+	return strings.HasPrefix(did, "did:web:")
+}
+
+// DocumentURL returns the HTTPS URL a did:web DID document is served from
+func DocumentURL(did string) (string, error) {
+	if !IsDIDWeb(did) || syntax.ValidateDID(did) != nil {
+		return "", ErrInvalidDID
+	}
+
+	segments := strings.Split(strings.TrimPrefix(did, "did:web:"), ":")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil || decoded == "" || strings.Contains(decoded, "/") {
+			return "", ErrInvalidDID
+		}
+		segments[i] = decoded
+	}
+
+	// Without a path the document lives at the well-known location of the host
+	if len(segments) == 1 {
+		return fmt.Sprintf("https://%s/.well-known/did.json", segments[0]), nil
+	}
+
+	return fmt.Sprintf("https://%s/%s/did.json", segments[0], strings.Join(segments[1:], "/")), nil
+}
+
+// Resolve fetches and decodes the DID document for a did:web DID
+func Resolve(ctx context.Context, client *http.Client, did string) (*Document, error) {
+	ctx, span := tracer.Start(ctx, "Resolve")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	docURL, err := DocumentURL(did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", docURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("didweb: failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("didweb: failed to fetch DID document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("didweb: failed to fetch DID document: %s", resp.Status)
+	}
+
+	doc := &Document{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(doc); err != nil {
+		return nil, fmt.Errorf("didweb: failed to decode DID document: %w", err)
+	}
+
+	if doc.ID != did {
+		return nil, fmt.Errorf("didweb: DID document id %q does not match %q", doc.ID, did)
+	}
+
+	return doc, nil
+}
+
+// Handle returns the normalized handle claimed by the document or an empty string if it doesn't claim a valid one
+func (doc *Document) Handle() string {
+	for _, aka := range doc.AlsoKnownAs {
+		if strings.HasPrefix(aka, "at://") {
+			handle, err := syntax.NormalizeHandle(strings.TrimPrefix(aka, "at://"))
+			if err != nil {
+				return ""
+			}
+			return handle
+		}
+	}
+	return ""
+}
+
+// Fragment returns the part of a DID URL after the '#', so both "#atproto" and "did:web:example.com#atproto" become "atproto"
+func Fragment(id string) string {
+	if idx := strings.LastIndex(id, "#"); idx >= 0 {
+		return id[idx+1:]
+	}
+	return id
+}
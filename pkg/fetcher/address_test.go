@@ -0,0 +1,84 @@
+package fetcher
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestForbidden(t *testing.T) {
+	tests := []struct {
+		addr      string
+		forbidden bool
+	}{
+		{"127.0.0.1", true},
+		{"127.1.2.3", true},
+		{"::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"10.0.0.1", true},
+		{"172.16.5.4", true},
+		{"172.31.255.255", true},
+		{"192.168.1.1", true},
+		{"100.64.0.1", true},
+		// Link-local, including the cloud metadata endpoint
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		// Unique local addresses, all of fc00::/7
+		{"fc00::1", true},
+		{"fd12:3456::1", true},
+		// IPv4 mapped into IPv6 is checked as the IPv4 address it carries
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"::ffff:169.254.169.254", true},
+		// NAT64 and 6to4 can embed any IPv4 address
+		{"64:ff9b::7f00:1", true},
+		{"64:ff9b:1::a00:1", true},
+		{"2002:7f00:1::1", true},
+		{"2002:808:808::1", true},
+		{"224.0.0.1", true},
+		{"ff02::1", true},
+		{"255.255.255.255", true},
+		{"192.0.2.1", true},
+		{"2001:db8::1", true},
+
+		{"8.8.8.8", false},
+		{"1.1.1.1", false},
+		{"172.32.0.1", false},
+		{"::ffff:8.8.8.8", false},
+		{"2606:4700:4700::1111", false},
+		{"2001:4860:4860::8888", false},
+	}
+
+	for _, tt := range tests {
+		addr := netip.MustParseAddr(tt.addr)
+		if got := forbidden(addr); got != tt.forbidden {
+			t.Errorf("forbidden(%s) = %t, want %t", tt.addr, got, tt.forbidden)
+		}
+	}
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    error
+	}{
+		{"127.0.0.1:443", ErrForbiddenAddress},
+		{"[::ffff:127.0.0.1]:443", ErrForbiddenAddress},
+		{"[fd00::1]:443", ErrForbiddenAddress},
+		{"169.254.169.254:80", ErrForbiddenAddress},
+		{"8.8.8.8:443", nil},
+		{"[2606:4700:4700::1111]:443", nil},
+	}
+
+	for _, tt := range tests {
+		if err := checkAddress("tcp", tt.address, nil); !errors.Is(err, tt.want) {
+			t.Errorf("checkAddress(%s) = %v, want %v", tt.address, err, tt.want)
+		}
+	}
+
+	// Control only ever sees resolved addresses, anything else is an error rather than allowed
+	if err := checkAddress("tcp", "example.com:443", nil); err == nil {
+		t.Error("checkAddress accepted a hostname")
+	}
+}
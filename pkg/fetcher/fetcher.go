@@ -0,0 +1,152 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("bingo/fetcher")
+
+const (
+	dialTimeout           = 5 * time.Second
+	tlsHandshakeTimeout   = 5 * time.Second
+	responseHeaderTimeout = 5 * time.Second
+	// requestTimeout bounds a whole request, including following redirects and reading the body
+	requestTimeout = 15 * time.Second
+
+	// DefaultMaxBodySize is plenty for a /.well-known/atproto-did response, which is a single DID
+	DefaultMaxBodySize = 64 * 1024
+)
+
+var (
+	ErrBodyTooLarge     = errors.New("response body too large")
+	ErrTooManyRedirects = errors.New("too many redirects")
+	ErrInsecureRedirect = errors.New("refusing to follow redirect away from https")
+)
+
+// Fetcher fetches small documents from hosts named by untrusted input, like handles and did:web DIDs.
+// It refuses to connect to internal addresses, bounds every phase of a request and the size of the body,
+// and doesn't follow redirects unless MaxRedirects allows it.
+type Fetcher struct {
+	// Client is the hardened client Get uses, it can be used directly by callers that bound the body themselves
+	Client      *http.Client
+	MaxBodySize int64
+	// MaxRedirects is the number of https redirects followed, with 0 the redirect response itself is returned
+	MaxRedirects int
+}
+
+// Response is a fetched response with its body already read
+type Response struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func New() *Fetcher {
+	return newFetcher(guardedDialer().DialContext, nil)
+}
+
+// guardedDialer returns a dialer that refuses to connect to forbidden addresses
+func guardedDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout: dialTimeout,
+		// Control sees the address after DNS resolution, so a handle that resolves to an internal address
+		// is refused no matter how the name was looked up
+		Control: checkAddress,
+	}
+}
+
+// newFetcher returns a fetcher that connects with dial, tlsConfig is nil outside of tests
+func newFetcher(dial func(ctx context.Context, network, addr string) (net.Conn, error), tlsConfig *tls.Config) *Fetcher {
+	transport := &http.Transport{
+		// Proxies would connect on our behalf and bypass the address check
+		Proxy:                 nil,
+		DialContext:           dial,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   2,
+		ForceAttemptHTTP2:     true,
+	}
+
+	f := &Fetcher{
+		MaxBodySize: DefaultMaxBodySize,
+	}
+	f.Client = &http.Client{
+		Transport:     otelhttp.NewTransport(transport),
+		Timeout:       requestTimeout,
+		CheckRedirect: f.checkRedirect,
+	}
+
+	return f
+}
+
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if f.MaxRedirects == 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > f.MaxRedirects {
+		return ErrTooManyRedirects
+	}
+	if req.URL.Scheme != "https" {
+		return ErrInsecureRedirect
+	}
+	return nil
+}
+
+// Get fetches an https URL and reads up to MaxBodySize of its body
+func (f *Fetcher) Get(ctx context.Context, rawURL string) (*Response, error) {
+	ctx, span := tracer.Start(ctx, "Get")
+	defer span.End()
+	span.SetAttributes(attribute.String("url", rawURL))
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetcher: invalid URL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("fetcher: refusing to fetch non-https URL %q", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetcher: failed to create request: %w", err)
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("status_code", resp.StatusCode))
+
+	// Read one byte past the limit to tell a body that fits exactly from one that doesn't
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetcher: failed to read response body: %w", err)
+	}
+	if int64(len(body)) > f.MaxBodySize {
+		return nil, fmt.Errorf("fetcher: %w, more than %d bytes", ErrBodyTooLarge, f.MaxBodySize)
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+	}, nil
+}
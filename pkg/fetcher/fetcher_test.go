@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// publicHost is routed to the test server, every other host goes through the guarded dialer
+const publicHost = "public.test"
+
+// newTestFetcher returns a fetcher that reaches srv as publicHost and dials anything else as New would
+func newTestFetcher(srv *httptest.Server) *Fetcher {
+	guarded := guardedDialer()
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, _, _ := net.SplitHostPort(addr); host == publicHost {
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+	return newFetcher(dial, &tls.Config{InsecureSkipVerify: true})
+}
+
+func TestGetRefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("reached"))
+	}))
+	defer srv.Close()
+
+	// The test server listens on loopback, so the real fetcher can't reach it
+	_, err := New().Get(context.Background(), srv.URL)
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("Get(%s) = %v, want ErrForbiddenAddress", srv.URL, err)
+	}
+
+	if _, err := New().Get(context.Background(), "http://"+publicHost+"/"); err == nil {
+		t.Error("Get accepted a non-https URL")
+	}
+}
+
+func TestGetRedirects(t *testing.T) {
+	var loopback string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/internal":
+			http.Redirect(w, r, "https://"+loopback+"/", http.StatusFound)
+		case r.URL.Path == "/insecure":
+			http.Redirect(w, r, "http://"+publicHost+"/done", http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/hops/"):
+			// /hops/n redirects n more times before landing on /done
+			n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hops/"))
+			if n == 0 {
+				http.Redirect(w, r, "/done", http.StatusFound)
+				return
+			}
+			http.Redirect(w, r, "/hops/"+strconv.Itoa(n-1), http.StatusFound)
+		case r.URL.Path == "/done":
+			w.Write([]byte("done"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	loopback = srv.Listener.Addr().String()
+
+	ctx := context.Background()
+	base := "https://" + publicHost
+
+	f := newTestFetcher(srv)
+	f.MaxRedirects = 3
+
+	resp, err := f.Get(ctx, base+"/hops/2")
+	if err != nil || string(resp.Body) != "done" {
+		t.Fatalf("Get within MaxRedirects = %v, %v", resp, err)
+	}
+
+	if _, err := f.Get(ctx, base+"/hops/3"); !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Get past MaxRedirects = %v, want ErrTooManyRedirects", err)
+	}
+
+	if _, err := f.Get(ctx, base+"/internal"); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Get redirected to an internal address = %v, want ErrForbiddenAddress", err)
+	}
+
+	if _, err := f.Get(ctx, base+"/insecure"); !errors.Is(err, ErrInsecureRedirect) {
+		t.Errorf("Get redirected to http = %v, want ErrInsecureRedirect", err)
+	}
+
+	// Without redirects allowed the redirect itself is the response
+	f.MaxRedirects = 0
+	resp, err = f.Get(ctx, base+"/hops/0")
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Errorf("Get with no redirects allowed = %v, %v, want the 302", resp, err)
+	}
+}
+
+func TestGetMaxBodySize(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		w.Write([]byte(strings.Repeat("x", n)))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	f := newTestFetcher(srv)
+	f.MaxBodySize = 1024
+
+	resp, err := f.Get(ctx, "https://"+publicHost+"/1024")
+	if err != nil || len(resp.Body) != 1024 {
+		t.Fatalf("Get of a body of exactly MaxBodySize = %v, %v", resp, err)
+	}
+
+	if _, err := f.Get(ctx, "https://"+publicHost+"/1025"); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Get of a body over MaxBodySize = %v, want ErrBodyTooLarge", err)
+	}
+}
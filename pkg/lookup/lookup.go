@@ -2,32 +2,92 @@ package lookup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"time"
 
 	"connectrpc.com/connect"
 	protovalidate "github.com/bufbuild/protovalidate-go"
 	bingov1 "github.com/ericvolp12/bingo/gen/bingo/v1"
+	"github.com/ericvolp12/bingo/pkg/didweb"
+	"github.com/ericvolp12/bingo/pkg/plc"
 	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/ericvolp12/bingo/pkg/syntax"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Server struct {
 	Store     *store.Store
+	Directory *plc.Directory
 	validator *protovalidate.Validator
+
+	// RevalidateWait is how long Revalidate waits for results before returning just the job ID
+	RevalidateWait time.Duration
+
+	// Freshness is how long since an entry was checked before lookups queue it for revalidation
+	Freshness time.Duration
+
+	// RevalidateTimeout bounds a synchronous revalidation, which is shared by every lookup waiting on the same DID
+	RevalidateTimeout time.Duration
+
+	// revalidations collapses concurrent synchronous revalidations of the same DID
+	revalidations singleflight.Group
 }
 
-func NewServer(store *store.Store) *Server {
+func NewServer(store *store.Store, directory *plc.Directory) *Server {
 	v, err := protovalidate.New()
 	if err != nil {
 		fmt.Println("failed to initialize validator:", err)
 	}
 	return &Server{
 		Store:     store,
+		Directory: directory,
 		validator: v,
+
+		RevalidateWait:    10 * time.Second,
+		Freshness:         12 * time.Hour,
+		RevalidateTimeout: 30 * time.Second,
+	}
+}
+
+var validationStatuses = map[store.ValidationStatus]bingov1.ValidationStatus{
+	store.ValidationStatusValid:           bingov1.ValidationStatus_VALIDATION_STATUS_VALID,
+	store.ValidationStatusUnclaimed:       bingov1.ValidationStatus_VALIDATION_STATUS_UNCLAIMED,
+	store.ValidationStatusDocumentError:   bingov1.ValidationStatus_VALIDATION_STATUS_DOCUMENT_ERROR,
+	store.ValidationStatusNXDomain:        bingov1.ValidationStatus_VALIDATION_STATUS_NXDOMAIN,
+	store.ValidationStatusTXTMismatch:     bingov1.ValidationStatus_VALIDATION_STATUS_TXT_MISMATCH,
+	store.ValidationStatusDNSError:        bingov1.ValidationStatus_VALIDATION_STATUS_DNS_ERROR,
+	store.ValidationStatusHTTPStatus:      bingov1.ValidationStatus_VALIDATION_STATUS_HTTP_STATUS,
+	store.ValidationStatusTLSError:        bingov1.ValidationStatus_VALIDATION_STATUS_TLS_ERROR,
+	store.ValidationStatusTimeout:         bingov1.ValidationStatus_VALIDATION_STATUS_TIMEOUT,
+	store.ValidationStatusConnectionError: bingov1.ValidationStatus_VALIDATION_STATUS_CONNECTION_ERROR,
+	store.ValidationStatusBodyMismatch:    bingov1.ValidationStatus_VALIDATION_STATUS_BODY_MISMATCH,
+	store.ValidationStatusBodyTooLarge:    bingov1.ValidationStatus_VALIDATION_STATUS_BODY_TOO_LARGE,
+	store.ValidationStatusNoRecord:        bingov1.ValidationStatus_VALIDATION_STATUS_NO_RECORD,
+}
+
+// lookupResponse converts a stored entry into its RPC representation
+func lookupResponse(entry *store.Entry) *bingov1.LookupResponse {
+	return &bingov1.LookupResponse{
+		Handle:           entry.Handle,
+		Did:              entry.Did,
+		IsValid:          entry.IsValid,
+		LastCheckedTime:  timestamppb.New(entry.LastCheckedTime),
+		ValidationStatus: validationStatuses[entry.ValidationStatus],
+		ValidationDetail: entry.ValidationDetail,
 	}
 }
 
+// invalidIdentifier reports whether err is caused by a malformed handle or DID in the request
+func invalidIdentifier(err error) bool {
+	return errors.Is(err, syntax.ErrInvalidHandle) || errors.Is(err, syntax.ErrInvalidDID)
+}
+
 func (s *Server) Lookup(
 	ctx context.Context,
 	req *connect.Request[bingov1.LookupRequest],
@@ -38,21 +98,82 @@ func (s *Server) Lookup(
 	}
 
 	entry, err := s.Store.Lookup(ctx, req.Msg.HandleOrDid)
+	if errors.Is(err, store.ErrNotFound) && didweb.IsDIDWeb(req.Msg.HandleOrDid) {
+		// did:web identities aren't in the PLC export, resolve them on demand
+		entry, err = s.Directory.ResolveDIDWeb(ctx, req.Msg.HandleOrDid)
+	}
 	if err != nil {
+		if invalidIdentifier(err) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
 		return nil, connect.NewError(connect.CodeNotFound, err)
 	}
 
-	res := connect.NewResponse(&bingov1.LookupResponse{
-		Handle:          entry.Handle,
-		Did:             entry.Did,
-		IsValid:         entry.IsValid,
-		LastCheckedTime: timestamppb.New(entry.LastCheckedTime),
-	})
+	entry, err = s.freshen(ctx, entry, req.Msg.MaxStaleness)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnavailable, err)
+	}
+
+	res := connect.NewResponse(lookupResponse(entry))
 
 	res.Header().Set("Bingo-Version", "v1")
 	return res, nil
 }
 
+// freshen returns an entry that was checked within the caller's max staleness, revalidating it first if needed.
+// Without a max staleness, entries older than Freshness are returned as they are and queued for revalidation.
+func (s *Server) freshen(ctx context.Context, entry *store.Entry, maxStaleness *durationpb.Duration) (*store.Entry, error) {
+	age := time.Since(entry.LastCheckedTime)
+
+	if maxStaleness != nil && age > maxStaleness.AsDuration() {
+		// The revalidation is shared with other callers, so it mustn't be canceled along with this one
+		results := s.revalidations.DoChan(entry.Did, func() (interface{}, error) {
+			ctx, cancel := context.WithTimeout(detach(ctx), s.RevalidateTimeout)
+			defer cancel()
+			return s.Directory.Revalidate(ctx, entry.Did, "")
+		})
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to revalidate stale entry: %w", ctx.Err())
+		case res := <-results:
+			if res.Err != nil {
+				return nil, fmt.Errorf("failed to revalidate stale entry: %w", res.Err)
+			}
+			return res.Val.(*store.Entry), nil
+		}
+	}
+
+	s.queueStale(ctx, []*store.Entry{entry})
+
+	return entry, nil
+}
+
+// detachedContext keeps the values of a context, like its trace, without its deadline or cancelation
+type detachedContext struct{ context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// detach returns a context that carries the values of ctx but is never canceled
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+// queueStale queues entries older than Freshness for revalidation in the background
+func (s *Server) queueStale(ctx context.Context, entries []*store.Entry) {
+	var dids []string
+	for _, entry := range entries {
+		if time.Since(entry.LastCheckedTime) > s.Freshness {
+			dids = append(dids, entry.Did)
+		}
+	}
+
+	if err := s.Directory.QueueRevalidation(ctx, dids, plc.PriorityStale); err != nil {
+		log.Printf("failed to queue stale entries for revalidation: %+v", err)
+	}
+}
+
 func (s *Server) BulkLookup(
 	ctx context.Context,
 	req *connect.Request[bingov1.BulkLookupRequest],
@@ -67,6 +188,11 @@ func (s *Server) BulkLookup(
 	handles := []string{}
 
 	for _, handleOrDid := range req.Msg.HandlesOrDids {
+		// Malformed identifiers can't match anything, so they're left out of the responses like unknown ones
+		handleOrDid, err := syntax.NormalizeIdentifier(handleOrDid)
+		if err != nil {
+			continue
+		}
 		if store.IsDID(handleOrDid) {
 			dids = append(dids, handleOrDid)
 		} else {
@@ -82,13 +208,9 @@ func (s *Server) BulkLookup(
 			return nil, connect.NewError(connect.CodeInternal, err)
 		}
 		for _, entry := range didEntries {
-			responses = append(responses, &bingov1.LookupResponse{
-				Handle:          entry.Handle,
-				Did:             entry.Did,
-				IsValid:         entry.IsValid,
-				LastCheckedTime: timestamppb.New(entry.LastCheckedTime),
-			})
+			responses = append(responses, lookupResponse(entry))
 		}
+		s.queueStale(ctx, didEntries)
 	}
 	if len(handles) > 0 {
 		handleEntries, err := s.Store.BulkLookupByHandle(ctx, handles)
@@ -96,13 +218,9 @@ func (s *Server) BulkLookup(
 			return nil, connect.NewError(connect.CodeInternal, err)
 		}
 		for _, entry := range handleEntries {
-			responses = append(responses, &bingov1.LookupResponse{
-				Handle:          entry.Handle,
-				Did:             entry.Did,
-				IsValid:         entry.IsValid,
-				LastCheckedTime: timestamppb.New(entry.LastCheckedTime),
-			})
+			responses = append(responses, lookupResponse(entry))
 		}
+		s.queueStale(ctx, handleEntries)
 	}
 
 	res := connect.NewResponse(&bingov1.BulkLookupResponse{
@@ -112,3 +230,259 @@ func (s *Server) BulkLookup(
 	res.Header().Set("Bingo-Version", "v1")
 	return res, nil
 }
+
+func (s *Server) GetHandleHistory(
+	ctx context.Context,
+	req *connect.Request[bingov1.GetHandleHistoryRequest],
+) (*connect.Response[bingov1.GetHandleHistoryResponse], error) {
+	log.Println("GetHandleHistory called")
+	if err := s.validator.Validate(req.Msg); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	handleOrDid, err := syntax.NormalizeIdentifier(req.Msg.HandleOrDid)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var claims []*store.HandleClaim
+	if store.IsDID(handleOrDid) {
+		claims, err = s.Store.GetHandleHistoryByDID(ctx, handleOrDid)
+	} else {
+		claims, err = s.Store.GetHandleHistoryByHandle(ctx, handleOrDid)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	responseClaims := []*bingov1.HandleClaim{}
+	for _, claim := range claims {
+		responseClaims = append(responseClaims, &bingov1.HandleClaim{
+			Handle:    claim.Handle,
+			Did:       claim.Did,
+			Cid:       claim.Cid,
+			CreatedAt: timestamppb.New(claim.CreatedAt),
+		})
+	}
+
+	res := connect.NewResponse(&bingov1.GetHandleHistoryResponse{
+		Claims: responseClaims,
+	})
+
+	res.Header().Set("Bingo-Version", "v1")
+	return res, nil
+}
+
+var didDocumentContext = []string{
+	"https://www.w3.org/ns/did/v1",
+	"https://w3id.org/security/multikey/v1",
+	"https://w3id.org/security/suites/secp256k1-2019/v1",
+}
+
+func (s *Server) ResolveDID(
+	ctx context.Context,
+	req *connect.Request[bingov1.ResolveDIDRequest],
+) (*connect.Response[bingov1.ResolveDIDResponse], error) {
+	log.Println("ResolveDID called")
+	if err := s.validator.Validate(req.Msg); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	doc, err := s.Store.GetDIDDocument(ctx, req.Msg.Did)
+	if errors.Is(err, store.ErrNotFound) && didweb.IsDIDWeb(req.Msg.Did) {
+		// did:web identities aren't in the PLC export, resolve them on demand
+		if _, err = s.Directory.ResolveDIDWeb(ctx, req.Msg.Did); err == nil {
+			doc, err = s.Store.GetDIDDocument(ctx, req.Msg.Did)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	document := &bingov1.DIDDocument{
+		Context:     didDocumentContext,
+		Id:          doc.Did,
+		AlsoKnownAs: doc.AlsoKnownAs,
+	}
+
+	// Sort map keys so responses are stable
+	methodIDs := make([]string, 0, len(doc.VerificationMethods))
+	for id := range doc.VerificationMethods {
+		methodIDs = append(methodIDs, id)
+	}
+	sort.Strings(methodIDs)
+	for _, id := range methodIDs {
+		document.VerificationMethod = append(document.VerificationMethod, &bingov1.VerificationMethod{
+			Id:                 fmt.Sprintf("%s#%s", doc.Did, id),
+			Type:               "Multikey",
+			Controller:         doc.Did,
+			PublicKeyMultibase: strings.TrimPrefix(doc.VerificationMethods[id], "did:key:"),
+		})
+	}
+
+	serviceIDs := make([]string, 0, len(doc.Services))
+	for id := range doc.Services {
+		serviceIDs = append(serviceIDs, id)
+	}
+	sort.Strings(serviceIDs)
+	for _, id := range serviceIDs {
+		document.Service = append(document.Service, &bingov1.Service{
+			Id:              fmt.Sprintf("#%s", id),
+			Type:            doc.Services[id].Type,
+			ServiceEndpoint: doc.Services[id].Endpoint,
+		})
+	}
+
+	res := connect.NewResponse(&bingov1.ResolveDIDResponse{
+		Document:     document,
+		RotationKeys: doc.RotationKeys,
+		UpdatedAt:    timestamppb.New(doc.UpdatedAt),
+		ChainInvalid: doc.ChainInvalid,
+		ChainError:   doc.ChainError,
+	})
+
+	res.Header().Set("Bingo-Version", "v1")
+	return res, nil
+}
+
+func (s *Server) Revalidate(
+	ctx context.Context,
+	req *connect.Request[bingov1.RevalidateRequest],
+) (*connect.Response[bingov1.RevalidateResponse], error) {
+	log.Println("Revalidate called")
+	if err := s.validator.Validate(req.Msg); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	// Handles are revalidated by the DID they currently belong to
+	seen := map[string]bool{}
+	dids := []string{}
+	failed := map[string]*plc.RevalidationResult{}
+	for _, handleOrDid := range req.Msg.HandlesOrDids {
+		did, err := syntax.NormalizeIdentifier(handleOrDid)
+		if err != nil {
+			failed[handleOrDid] = &plc.RevalidationResult{Error: err.Error()}
+			continue
+		}
+		if !store.IsDID(did) {
+			entry, err := s.Store.Lookup(ctx, did)
+			if errors.Is(err, store.ErrNotFound) {
+				failed[handleOrDid] = &plc.RevalidationResult{Error: "handle not found"}
+				continue
+			}
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			did = entry.Did
+		}
+		if !seen[did] {
+			seen[did] = true
+			dids = append(dids, did)
+		}
+	}
+
+	// A single identifier jumps ahead of batches
+	priority := plc.PriorityBatch
+	if len(req.Msg.HandlesOrDids) == 1 {
+		priority = plc.PrioritySingle
+	}
+
+	id, err := s.Directory.EnqueueRevalidation(ctx, dids, failed, priority)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	job, err := s.waitForRevalidation(ctx, id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := connect.NewResponse(&bingov1.RevalidateResponse{
+		Job: revalidationJob(job),
+	})
+
+	res.Header().Set("Bingo-Version", "v1")
+	return res, nil
+}
+
+// waitForRevalidation polls a job until it's done or RevalidateWait passes, returning it as it stands
+func (s *Server) waitForRevalidation(ctx context.Context, id string) (*plc.RevalidationJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.RevalidateWait)
+	defer cancel()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.Directory.GetRevalidationJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Done() {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			// Hand back the job ID with whatever results are in, using a fresh context since ours expired
+			return s.Directory.GetRevalidationJob(context.Background(), id)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) GetRevalidationJob(
+	ctx context.Context,
+	req *connect.Request[bingov1.GetRevalidationJobRequest],
+) (*connect.Response[bingov1.GetRevalidationJobResponse], error) {
+	log.Println("GetRevalidationJob called")
+	if err := s.validator.Validate(req.Msg); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	job, err := s.Directory.GetRevalidationJob(ctx, req.Msg.Id)
+	if errors.Is(err, plc.ErrJobNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := connect.NewResponse(&bingov1.GetRevalidationJobResponse{
+		Job: revalidationJob(job),
+	})
+
+	res.Header().Set("Bingo-Version", "v1")
+	return res, nil
+}
+
+// revalidationJob converts a job into its RPC representation, leaving out pending results
+func revalidationJob(job *plc.RevalidationJob) *bingov1.RevalidationJob {
+	out := &bingov1.RevalidationJob{
+		Id:   job.ID,
+		Done: job.Done(),
+	}
+
+	for identifier, result := range job.Results {
+		if result == nil {
+			continue
+		}
+		r := &bingov1.RevalidationResult{
+			HandleOrDid: identifier,
+			Error:       result.Error,
+		}
+		if result.Entry != nil {
+			r.Entry = lookupResponse(result.Entry)
+		}
+		out.Results = append(out.Results, r)
+	}
+
+	sort.Slice(out.Results, func(i, j int) bool {
+		return out.Results[i].HandleOrDid < out.Results[j].HandleOrDid
+	})
+
+	return out
+}
@@ -0,0 +1,262 @@
+package plc
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/mr-tron/base58"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var plcOperationAuditCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "plc_operation_audit_total",
+	Help: "Total number of PLC operations checked in audit mode by result",
+}, []string{"result"})
+
+// errUnverifiable is returned when an operation can't be checked locally, usually because
+// its previous operation was ingested before the operation log existed
+var errUnverifiable = errors.New("operation cannot be verified locally")
+
+var (
+	// Multicodec varint prefixes for compressed public keys in did:key identifiers
+	secp256k1Multicodec = []byte{0xe7, 0x01}
+	p256Multicodec      = []byte{0x80, 0x24}
+
+	// CIDv1, dag-cbor codec, sha2-256 multihash of 32 bytes
+	cidPrefix = []byte{0x01, 0x71, 0x12, 0x20}
+)
+
+var base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// dagCBOR uses the length-first map key ordering DAG-CBOR requires
+var dagCBOR, _ = cbor.EncOptions{Sort: cbor.SortCanonical}.EncMode()
+
+// auditOperation verifies an operation's signature and prev linkage and flags the DID if they don't check out.
+// It runs in the batch's transaction, so prevs recorded earlier in the same batch are found.
+func (d *Directory) auditOperation(ctx context.Context, tx *store.Tx, entry *DirectoryJSONLRow) {
+	ctx, span := tracer.Start(ctx, "auditOperation")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", entry.Did), attribute.String("cid", entry.Cid))
+
+	err := verifyOperation(ctx, entry, tx.GetOperation)
+	switch {
+	case err == nil:
+		plcOperationAuditCounter.WithLabelValues("verified").Inc()
+	case errors.Is(err, errUnverifiable):
+		plcOperationAuditCounter.WithLabelValues("skipped").Inc()
+	default:
+		plcOperationAuditCounter.WithLabelValues("invalid").Inc()
+		d.Logger.Warnw("PLC operation failed verification",
+			"did", entry.Did,
+			"cid", entry.Cid,
+			"error", err,
+		)
+		if err := tx.FlagDIDChainInvalid(ctx, entry.Did, fmt.Sprintf("%s: %s", entry.Cid, err)); err != nil {
+			d.Logger.Errorf("failed to flag DID chain: %+v", err)
+		}
+	}
+}
+
+// verifyOperation checks that an operation's CID matches its contents, that it links to a previous
+// operation of the same DID and that it's signed by one of that operation's rotation keys.
+// Genesis operations are checked against their own rotation keys and the DID they derive.
+// getOperation loads previous operations from the operation log.
+func verifyOperation(ctx context.Context, entry *DirectoryJSONLRow, getOperation func(ctx context.Context, cid string) (*store.Operation, error)) error {
+	op, err := decodeRawOperation(entry.RawOperation)
+	if err != nil {
+		return err
+	}
+
+	signed, err := dagCBOR.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to encode operation: %w", err)
+	}
+
+	if cid := operationCID(signed); cid != entry.Cid {
+		return fmt.Errorf("CID mismatch, computed %s", cid)
+	}
+
+	sigStr, _ := op["sig"].(string)
+	sig, err := base64.RawURLEncoding.DecodeString(sigStr)
+	if err != nil || len(sig) != 64 {
+		return fmt.Errorf("malformed signature")
+	}
+
+	delete(op, "sig")
+	unsigned, err := dagCBOR.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to encode unsigned operation: %w", err)
+	}
+
+	var rotationKeys []string
+	prev, _ := op["prev"].(string)
+	if prev == "" {
+		if entry.Operation.Type == opTypeTombstone {
+			return fmt.Errorf("genesis operation is a tombstone")
+		}
+		if did := genesisDID(signed); did != entry.Did {
+			return fmt.Errorf("genesis operation derives %s", did)
+		}
+		rotationKeys = entry.Operation.RotationKeys
+	} else {
+		prevOp, err := getOperation(ctx, prev)
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("prev %s: %w", prev, errUnverifiable)
+		}
+		if err != nil {
+			return fmt.Errorf("prev %s: %w: %s", prev, errUnverifiable, err)
+		}
+		if prevOp.Did != entry.Did {
+			return fmt.Errorf("prev %s belongs to %s", prev, prevOp.Did)
+		}
+		if prevOp.CreatedAt.After(entry.CreatedAt) {
+			return fmt.Errorf("prev %s was created after the operation", prev)
+		}
+
+		var prevOperation Operation
+		if err := json.Unmarshal(prevOp.Operation, &prevOperation); err != nil {
+			return fmt.Errorf("failed to decode prev %s: %w", prev, err)
+		}
+		if prevOperation.Type == opTypeTombstone {
+			return fmt.Errorf("prev %s is a tombstone", prev)
+		}
+		rotationKeys = prevOperation.RotationKeys
+	}
+
+	hash := sha256.Sum256(unsigned)
+	for _, key := range rotationKeys {
+		if verifyDIDKeySignature(key, hash[:], sig) == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("signature does not match any rotation key")
+}
+
+// decodeRawOperation decodes an operation into generic values that re-encode to the original DAG-CBOR
+func decodeRawOperation(raw json.RawMessage) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var op map[string]any
+	if err := dec.Decode(&op); err != nil {
+		return nil, fmt.Errorf("failed to decode operation: %w", err)
+	}
+
+	normalized, err := normalizeNumbers(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalized.(map[string]any), nil
+}
+
+// normalizeNumbers converts JSON numbers to integers since DAG-CBOR has no place for JSON's floats
+func normalizeNumbers(v any) (any, error) {
+	switch val := v.(type) {
+	case json.Number:
+		i, err := val.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("operation contains a non-integer number %s", val)
+		}
+		return i, nil
+	case map[string]any:
+		for k, child := range val {
+			n, err := normalizeNumbers(child)
+			if err != nil {
+				return nil, err
+			}
+			val[k] = n
+		}
+		return val, nil
+	case []any:
+		for i, child := range val {
+			n, err := normalizeNumbers(child)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = n
+		}
+		return val, nil
+	default:
+		return v, nil
+	}
+}
+
+// operationCID computes the base32 CIDv1 of a DAG-CBOR encoded operation
+func operationCID(encoded []byte) string {
+	hash := sha256.Sum256(encoded)
+	return "b" + base32Lower.EncodeToString(append(append([]byte{}, cidPrefix...), hash[:]...))
+}
+
+// genesisDID derives the did:plc identifier from a signed genesis operation
+func genesisDID(encoded []byte) string {
+	hash := sha256.Sum256(encoded)
+	return "did:plc:" + base32Lower.EncodeToString(hash[:])[:24]
+}
+
+// verifyDIDKeySignature checks a compact low-S ECDSA signature of a hash against a secp256k1 or P-256 did:key
+func verifyDIDKeySignature(didKey string, hash []byte, sig []byte) error {
+	if !strings.HasPrefix(didKey, "did:key:z") {
+		return fmt.Errorf("unsupported did:key encoding")
+	}
+
+	keyBytes, err := base58.Decode(strings.TrimPrefix(didKey, "did:key:z"))
+	if err != nil {
+		return fmt.Errorf("failed to decode did:key: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(keyBytes, secp256k1Multicodec):
+		pub, err := secp256k1.ParsePubKey(keyBytes[len(secp256k1Multicodec):])
+		if err != nil {
+			return fmt.Errorf("failed to parse secp256k1 key: %w", err)
+		}
+
+		var r, s secp256k1.ModNScalar
+		if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) {
+			return fmt.Errorf("signature out of range")
+		}
+		if s.IsOverHalfOrder() {
+			return fmt.Errorf("signature is not low-S")
+		}
+		if !secp256k1ecdsa.NewSignature(&r, &s).Verify(hash, pub) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	case bytes.HasPrefix(keyBytes, p256Multicodec):
+		curve := elliptic.P256()
+		x, y := elliptic.UnmarshalCompressed(curve, keyBytes[len(p256Multicodec):])
+		if x == nil {
+			return fmt.Errorf("failed to parse P-256 key")
+		}
+
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if s.Cmp(new(big.Int).Rsh(curve.Params().N, 1)) > 0 {
+			return fmt.Errorf("signature is not low-S")
+		}
+		if !ecdsa.Verify(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}, hash, r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported did:key type")
+	}
+}
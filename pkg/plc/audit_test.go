@@ -0,0 +1,138 @@
+package plc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ericvolp12/bingo/pkg/store"
+)
+
+// auditFixtures are PLC operations signed with fixed secp256k1 and P-256 keys by testdata/gen_audit_fixtures.go
+type auditFixtures struct {
+	Keys  map[string]string `json:"keys"`
+	Log   []json.RawMessage `json:"log"`
+	Cases []struct {
+		Name string          `json:"name"`
+		Row  json.RawMessage `json:"row"`
+		Err  string          `json:"err"`
+	} `json:"cases"`
+}
+
+func loadAuditFixtures(t *testing.T) *auditFixtures {
+	t.Helper()
+	data, err := os.ReadFile("testdata/audit_operations.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures := &auditFixtures{}
+	if err := json.Unmarshal(data, fixtures); err != nil {
+		t.Fatal(err)
+	}
+	return fixtures
+}
+
+func decodeRow(t *testing.T, raw json.RawMessage) *DirectoryJSONLRow {
+	t.Helper()
+	row := &DirectoryJSONLRow{}
+	if err := json.Unmarshal(raw, row); err != nil {
+		t.Fatal(err)
+	}
+	return row
+}
+
+func TestVerifyOperation(t *testing.T) {
+	fixtures := loadAuditFixtures(t)
+
+	// The operation log the fixtures' prevs point into
+	log := map[string]*store.Operation{}
+	for _, raw := range fixtures.Log {
+		row := decodeRow(t, raw)
+		log[row.Cid] = &store.Operation{
+			Cid:       row.Cid,
+			Did:       row.Did,
+			Operation: row.RawOperation,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	getOperation := func(ctx context.Context, cid string) (*store.Operation, error) {
+		if op, ok := log[cid]; ok {
+			return op, nil
+		}
+		return nil, store.ErrNotFound
+	}
+
+	for _, c := range fixtures.Cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := verifyOperation(context.Background(), decodeRow(t, c.Row), getOperation)
+			switch {
+			case c.Err == "" && err != nil:
+				t.Fatalf("verifyOperation: %v", err)
+			case c.Err != "" && err == nil:
+				t.Fatalf("verifyOperation succeeded, want an error containing %q", c.Err)
+			case c.Err != "" && !strings.Contains(err.Error(), c.Err):
+				t.Fatalf("verifyOperation: %v, want an error containing %q", err, c.Err)
+			}
+		})
+	}
+}
+
+func TestVerifyDIDKeySignatureRejectsHighS(t *testing.T) {
+	fixtures := loadAuditFixtures(t)
+
+	for _, c := range fixtures.Cases {
+		if !strings.HasSuffix(c.Name, "high-S") {
+			continue
+		}
+		t.Run(c.Name, func(t *testing.T) {
+			row := decodeRow(t, c.Row)
+			op, err := decodeRawOperation(row.RawOperation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := base64.RawURLEncoding.DecodeString(op["sig"].(string))
+			if err != nil {
+				t.Fatal(err)
+			}
+			delete(op, "sig")
+			unsigned, err := dagCBOR.Marshal(op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash := sha256.Sum256(unsigned)
+
+			key := fixtures.Keys["p256"]
+			if strings.HasPrefix(c.Name, "secp256k1") {
+				key = fixtures.Keys["secp256k1"]
+			}
+			if err := verifyDIDKeySignature(key, hash[:], sig); err == nil || !strings.Contains(err.Error(), "low-S") {
+				t.Fatalf("verifyDIDKeySignature: %v, want a low-S error", err)
+			}
+		})
+	}
+}
+
+func TestGenesisDID(t *testing.T) {
+	fixtures := loadAuditFixtures(t)
+	genesis := decodeRow(t, fixtures.Log[0])
+
+	op, err := decodeRawOperation(genesis.RawOperation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := dagCBOR.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cid := operationCID(encoded); cid != genesis.Cid {
+		t.Errorf("operationCID = %s, want %s", cid, genesis.Cid)
+	}
+	if did := genesisDID(encoded); did != genesis.Did {
+		t.Errorf("genesisDID = %s, want %s", did, genesis.Did)
+	}
+}
@@ -0,0 +1,83 @@
+package plc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Cursor tracks ingestion progress through the PLC export.
+// The export can only be paged by timestamp and many operations can share one,
+// so the CIDs already applied at the cursor's timestamp are tracked as well.
+// This lets ingestion resume mid-timestamp without skipping or replaying operations.
+type Cursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Cids      []string  `json:"cids"`
+}
+
+// exportPrecision is the granularity of createdAt timestamps in the PLC export
+const exportPrecision = time.Millisecond
+
+// After returns the value for the export's exclusive `after` parameter,
+// which includes any operations sharing the cursor's timestamp
+func (c *Cursor) After() time.Time {
+	if c.CreatedAt.IsZero() {
+		return c.CreatedAt
+	}
+	return c.CreatedAt.Add(-exportPrecision)
+}
+
+// Seen reports whether the row is at or before the cursor and has already been applied
+func (c *Cursor) Seen(row *DirectoryJSONLRow) bool {
+	if row.CreatedAt.Before(c.CreatedAt) {
+		return true
+	}
+	if !row.CreatedAt.Equal(c.CreatedAt) {
+		return false
+	}
+	for _, cid := range c.Cids {
+		if cid == row.Cid {
+			return true
+		}
+	}
+	return false
+}
+
+// Advance moves the cursor past the row
+func (c *Cursor) Advance(row *DirectoryJSONLRow) {
+	if row.CreatedAt.Equal(c.CreatedAt) {
+		c.Cids = append(c.Cids, row.Cid)
+		return
+	}
+	if row.CreatedAt.After(c.CreatedAt) {
+		c.CreatedAt = row.CreatedAt
+		c.Cids = []string{row.Cid}
+	}
+}
+
+// Clone returns a copy of the cursor that can be advanced independently
+func (c *Cursor) Clone() Cursor {
+	return Cursor{
+		CreatedAt: c.CreatedAt,
+		Cids:      append([]string{}, c.Cids...),
+	}
+}
+
+func (c *Cursor) String() string {
+	return fmt.Sprintf("%s (%d cids)", c.CreatedAt.Format(time.RFC3339Nano), len(c.Cids))
+}
+
+// parseCursor decodes a stored cursor, accepting the legacy bare RFC3339 timestamp format
+func parseCursor(val string) (Cursor, error) {
+	var c Cursor
+	if err := json.Unmarshal([]byte(val), &c); err == nil {
+		return c, nil
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("failed to parse cursor: %w", err)
+	}
+
+	return Cursor{CreatedAt: createdAt}, nil
+}
@@ -0,0 +1,109 @@
+package plc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCursorAfter(t *testing.T) {
+	var zero Cursor
+	if !zero.After().IsZero() {
+		t.Errorf("After of an empty cursor = %s, want the zero time", zero.After())
+	}
+
+	// The export's after is exclusive, so it's moved back a millisecond to include the cursor's own timestamp
+	at := time.Date(2023, 9, 1, 12, 0, 0, 5e6, time.UTC)
+	c := Cursor{CreatedAt: at}
+	if want := at.Add(-time.Millisecond); !c.After().Equal(want) {
+		t.Errorf("After = %s, want %s", c.After(), want)
+	}
+}
+
+func TestCursorSeenAndAdvance(t *testing.T) {
+	at := time.Date(2023, 9, 1, 12, 0, 0, 5e6, time.UTC)
+	row := func(cid string, createdAt time.Time) *DirectoryJSONLRow {
+		return &DirectoryJSONLRow{Cid: cid, CreatedAt: createdAt}
+	}
+
+	c := Cursor{}
+	c.Advance(row("a", at))
+	c.Advance(row("b", at))
+
+	tests := []struct {
+		row  *DirectoryJSONLRow
+		seen bool
+	}{
+		{row("a", at), true},
+		{row("b", at), true},
+		// Another operation at the same millisecond hasn't been applied yet
+		{row("c", at), false},
+		{row("z", at.Add(-time.Millisecond)), true},
+		{row("d", at.Add(time.Millisecond)), false},
+	}
+	for _, tt := range tests {
+		if got := c.Seen(tt.row); got != tt.seen {
+			t.Errorf("Seen(%s at %s) = %t, want %t", tt.row.Cid, tt.row.CreatedAt.Format(time.RFC3339Nano), got, tt.seen)
+		}
+	}
+
+	// Rows from before the cursor don't move it
+	c.Advance(row("old", at.Add(-time.Second)))
+	if !c.CreatedAt.Equal(at) || len(c.Cids) != 2 {
+		t.Errorf("cursor moved back to %s", c.String())
+	}
+
+	// A later millisecond starts a new set of CIDs
+	later := at.Add(time.Millisecond)
+	c.Advance(row("d", later))
+	if !c.CreatedAt.Equal(later) || len(c.Cids) != 1 || c.Cids[0] != "d" {
+		t.Errorf("cursor after a later row = %s %v", c.String(), c.Cids)
+	}
+	if !c.Seen(row("a", at)) {
+		t.Error("rows before the new millisecond are no longer seen")
+	}
+}
+
+func TestCursorClone(t *testing.T) {
+	at := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	c := Cursor{CreatedAt: at, Cids: []string{"a"}}
+
+	clone := c.Clone()
+	clone.Advance(&DirectoryJSONLRow{Cid: "b", CreatedAt: at})
+	if len(c.Cids) != 1 {
+		t.Errorf("advancing a clone changed the original: %v", c.Cids)
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	at := time.Date(2023, 9, 1, 12, 0, 0, 123e6, time.UTC)
+
+	stored, err := json.Marshal(&Cursor{CreatedAt: at, Cids: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := parseCursor(string(stored))
+	if err != nil {
+		t.Fatalf("parseCursor(%s): %v", stored, err)
+	}
+	if !c.CreatedAt.Equal(at) || len(c.Cids) != 2 || c.Cids[0] != "a" || c.Cids[1] != "b" {
+		t.Errorf("parseCursor(%s) = %s %v", stored, c.String(), c.Cids)
+	}
+
+	// Cursors stored before CIDs were tracked are a bare timestamp
+	for _, legacy := range []string{"2023-09-01T12:00:00.123Z", "2023-09-01T14:00:00.123+02:00"} {
+		c, err := parseCursor(legacy)
+		if err != nil {
+			t.Fatalf("parseCursor(%s): %v", legacy, err)
+		}
+		if !c.CreatedAt.Equal(at) || len(c.Cids) != 0 {
+			t.Errorf("parseCursor(%s) = %s %v", legacy, c.String(), c.Cids)
+		}
+	}
+
+	for _, invalid := range []string{"", "yesterday", "2023-09-01"} {
+		if _, err := parseCursor(invalid); err == nil {
+			t.Errorf("parseCursor(%q) succeeded", invalid)
+		}
+	}
+}
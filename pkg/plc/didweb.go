@@ -0,0 +1,187 @@
+package plc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/didweb"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/ericvolp12/bingo/pkg/syntax"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrDIDWebUnresolvable is returned when a did:web DID document can't be fetched or is invalid
+var ErrDIDWebUnresolvable = errors.New("failed to resolve did:web")
+
+// didWebMissTTL is how long a did:web that failed to resolve is remembered,
+// so repeated lookups of it don't hit its host every time
+const didWebMissTTL = 5 * time.Minute
+
+func (d *Directory) didWebMissKey(did string) string {
+	return d.RedisPrefix + ":didweb_miss:" + did
+}
+
+// ResolveDIDWeb resolves a did:web DID document on demand, validates the handle it claims and persists the result.
+// Resolution failures are cached for a short while and returned again without fetching the document.
+func (d *Directory) ResolveDIDWeb(ctx context.Context, did string) (*store.Entry, error) {
+	ctx, span := tracer.Start(ctx, "ResolveDIDWeb")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	reason, err := d.RedisClient.Get(ctx, d.didWebMissKey(did)).Result()
+	if err == nil {
+		span.SetAttributes(attribute.Bool("cached_miss", true))
+		return nil, fmt.Errorf("%w: %s", ErrDIDWebUnresolvable, reason)
+	}
+	if !errors.Is(err, redis.Nil) {
+		d.Logger.Errorf("failed to check did:web miss cache for %s: %+v", did, err)
+	}
+
+	entry, err := d.Revalidate(ctx, did, "")
+	if errors.Is(err, ErrDIDWebUnresolvable) {
+		reason := strings.TrimPrefix(err.Error(), ErrDIDWebUnresolvable.Error()+": ")
+		if cacheErr := d.RedisClient.Set(ctx, d.didWebMissKey(did), reason, didWebMissTTL).Err(); cacheErr != nil {
+			d.Logger.Errorf("failed to cache did:web miss for %s: %+v", did, cacheErr)
+		}
+	}
+
+	return entry, err
+}
+
+// Revalidate validates a DID's handle immediately and persists the result.
+// A given handle is normalized first and only used if the DID document claims it, otherwise,
+// or if none is given, the handle is taken from the did:web document or the stored entry.
+func (d *Directory) Revalidate(ctx context.Context, did string, handle string) (*store.Entry, error) {
+	ctx, span := tracer.Start(ctx, "Revalidate")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	switch {
+	case handle != "":
+		normalized, err := syntax.NormalizeHandle(handle)
+		if err != nil {
+			return nil, err
+		}
+		// Anyone can name any handle, so it's never stored for the DID unless the DID document agrees.
+		// A DID whose operations haven't been ingested yet claims nothing until they are.
+		claimed, err := d.documentClaimsHandle(ctx, did, normalized)
+		if err != nil && !errors.Is(err, errDocumentUnavailable) {
+			return nil, fmt.Errorf("failed to check DID document for handle: %w", err)
+		}
+		if !claimed {
+			span.SetAttributes(attribute.Bool("unclaimed", true))
+			return d.Revalidate(ctx, did, "")
+		}
+		handle = normalized
+	case didweb.IsDIDWeb(did):
+		doc, err := didweb.Resolve(ctx, d.Fetcher.Client, did)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDIDWebUnresolvable, err)
+		}
+		if err := d.Store.UpdateDIDDocument(ctx, didWebDocument(doc)); err != nil {
+			return nil, err
+		}
+		handle = doc.Handle()
+	default:
+		entry, err := d.Store.Lookup(ctx, did)
+		if err != nil {
+			return nil, err
+		}
+		handle = entry.Handle
+	}
+
+	if handle == "" {
+		return nil, fmt.Errorf("no handle found for %s", did)
+	}
+
+	validation := d.ValidateHandle(ctx, did, handle)
+	if len(validation.Errors) != 0 {
+		d.Logger.Errorw("failed to validate handle",
+			"did", did,
+			"handle", handle,
+			"status", validation.Status,
+			"errors", validation.Errors,
+		)
+	}
+
+	prev, err := d.Store.GetEntry(ctx, did)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+
+	schedule := d.Settings().Schedule
+	entry := validation.Entry(&schedule, prev, did, handle)
+
+	if err := d.Store.Update(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	// Update only sets the handle for existing rows, so persist the validation result separately,
+	// without an owner so a lease another replica holds on the entry stays in place
+	if err := d.Store.BulkUpdateEntryValidation(ctx, "", []*store.Entry{entry}); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// refreshDIDWebHandle re-resolves a did:web DID document and updates the stored handle if it changed.
+// did:web identities don't show up in the PLC export, so this is the only way we learn about handle changes.
+func (d *Directory) refreshDIDWebHandle(ctx context.Context, did string, handle string) string {
+	doc, err := didweb.Resolve(ctx, d.Fetcher.Client, did)
+	if err != nil {
+		d.Logger.Errorw("failed to resolve did:web document", "did", did, "error", err)
+		return handle
+	}
+
+	if err := d.Store.UpdateDIDDocument(ctx, didWebDocument(doc)); err != nil {
+		d.Logger.Errorf("failed to update DID document: %+v", err)
+	}
+
+	newHandle := doc.Handle()
+	if newHandle == "" || newHandle == handle {
+		return handle
+	}
+
+	err = d.Store.Update(ctx, &store.Entry{
+		Did:     did,
+		Handle:  newHandle,
+		IsValid: false,
+	})
+	if err != nil {
+		d.Logger.Errorf("failed to update entry: %+v", err)
+		return handle
+	}
+
+	return newHandle
+}
+
+// didWebDocument normalizes a did:web DID document into the shape PLC operations use
+func didWebDocument(doc *didweb.Document) *store.DIDDocument {
+	verificationMethods := map[string]string{}
+	for _, vm := range doc.VerificationMethod {
+		if vm.PublicKeyMultibase == "" {
+			continue
+		}
+		verificationMethods[didweb.Fragment(vm.ID)] = "did:key:" + vm.PublicKeyMultibase
+	}
+
+	services := map[string]store.Service{}
+	for _, svc := range doc.Service {
+		services[didweb.Fragment(svc.ID)] = store.Service{
+			Type:     svc.Type,
+			Endpoint: svc.ServiceEndpoint,
+		}
+	}
+
+	return &store.DIDDocument{
+		Did:                 doc.ID,
+		AlsoKnownAs:         doc.AlsoKnownAs,
+		VerificationMethods: verificationMethods,
+		Services:            services,
+	}
+}
@@ -0,0 +1,153 @@
+package plc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/time/rate"
+)
+
+var domainLimitersGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "validation_domain_limiters",
+	Help: "Number of per-domain rate limiters currently tracked for HTTPS validation",
+})
+
+// DefaultDomainLimit is the HTTPS validation budget of a registrable domain, in requests per second
+const DefaultDomainLimit = rate.Limit(5)
+
+// DefaultDomainLimitOverrides raises the budget of domains known to host many handles
+var DefaultDomainLimitOverrides = map[string]rate.Limit{
+	"bsky.social": 20,
+}
+
+// DomainLimiter rate limits requests per registrable domain (eTLD+1), so every handle
+// hosted under one domain, like all the handles of a self-hosted PDS, shares one budget.
+// Limiters for domains that haven't been seen for IdleTTL are evicted.
+type DomainLimiter struct {
+	Limit rate.Limit
+	Burst int
+	// Overrides sets the limit for specific registrable domains, e.g. a higher one for bsky.social
+	Overrides map[string]rate.Limit
+	IdleTTL   time.Duration
+
+	lk        sync.Mutex
+	limiters  map[string]*domainLimiter
+	lastSweep time.Time
+}
+
+type domainLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func NewDomainLimiter(limit rate.Limit, burst int, overrides map[string]rate.Limit) *DomainLimiter {
+	return &DomainLimiter{
+		Limit:     limit,
+		Burst:     burst,
+		Overrides: overrides,
+		IdleTTL:   10 * time.Minute,
+
+		limiters:  map[string]*domainLimiter{},
+		lastSweep: time.Now(),
+	}
+}
+
+// SetLimits changes the default budget and the overrides, including for the domains already being limited
+func (l *DomainLimiter) SetLimits(limit rate.Limit, burst int, overrides map[string]rate.Limit) {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+
+	l.Limit = limit
+	l.Burst = burst
+	l.Overrides = overrides
+
+	for domain, dl := range l.limiters {
+		dl.limiter.SetLimit(l.domainLimit(domain))
+		dl.limiter.SetBurst(l.burst())
+	}
+}
+
+// Wait blocks until a request to host is allowed or ctx is done
+func (l *DomainLimiter) Wait(ctx context.Context, host string) error {
+	return l.limiter(Domain(host)).Wait(ctx)
+}
+
+func (l *DomainLimiter) limiter(domain string) *rate.Limiter {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > l.IdleTTL {
+		l.evict(now)
+	}
+
+	dl, ok := l.limiters[domain]
+	if !ok {
+		dl = &domainLimiter{limiter: rate.NewLimiter(l.domainLimit(domain), l.burst())}
+		l.limiters[domain] = dl
+		domainLimitersGauge.Set(float64(len(l.limiters)))
+	}
+	dl.lastUsed = now
+
+	return dl.limiter
+}
+
+func (l *DomainLimiter) domainLimit(domain string) rate.Limit {
+	if override, ok := l.Overrides[domain]; ok {
+		return override
+	}
+	return l.Limit
+}
+
+func (l *DomainLimiter) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+// evict drops limiters that have been idle for longer than IdleTTL, a fresh limiter starts with a full burst
+// so this only forgets domains that are back under their budget anyway
+func (l *DomainLimiter) evict(now time.Time) {
+	for domain, dl := range l.limiters {
+		if now.Sub(dl.lastUsed) > l.IdleTTL {
+			delete(l.limiters, domain)
+		}
+	}
+	l.lastSweep = now
+	domainLimitersGauge.Set(float64(len(l.limiters)))
+}
+
+// Domain returns the registrable domain (eTLD+1) of a host, or the host itself if it doesn't have one
+func Domain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return domain
+}
+
+// ParseDomainLimits parses overrides in the form domain=requests_per_second
+func ParseDomainLimits(overrides []string) (map[string]rate.Limit, error) {
+	limits := make(map[string]rate.Limit, len(overrides))
+	for _, override := range overrides {
+		domain, limit, ok := strings.Cut(override, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid domain rate limit %q, expected domain=requests_per_second", override)
+		}
+		rps, err := strconv.ParseFloat(limit, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid domain rate limit %q: %w", override, err)
+		}
+		limits[Domain(domain)] = rate.Limit(rps)
+	}
+	return limits, nil
+}
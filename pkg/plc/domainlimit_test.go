@@ -0,0 +1,108 @@
+package plc
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestDomain(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"alice.example.com", "example.com"},
+		{"a.b.example.com", "example.com"},
+		{"Alice.Example.COM.", "example.com"},
+		{"alice.bsky.social", "bsky.social"},
+		{"alice.example.co.uk", "example.co.uk"},
+		// Hosts under a public suffix that's run by someone else are limited separately
+		{"alice.github.io", "alice.github.io"},
+		{"example.com", "example.com"},
+		{"localhost", "localhost"},
+	}
+
+	for _, tt := range tests {
+		if got := Domain(tt.host); got != tt.want {
+			t.Errorf("Domain(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestDomainLimiterSharesRegistrableDomain(t *testing.T) {
+	l := NewDomainLimiter(5, 1, nil)
+
+	alice := l.limiter(Domain("alice.example.com"))
+	bob := l.limiter(Domain("bob.pds.example.com"))
+	if alice != bob {
+		t.Error("subdomains of the same registrable domain got separate limiters")
+	}
+	if other := l.limiter(Domain("alice.example.net")); other == alice {
+		t.Error("different registrable domains share a limiter")
+	}
+}
+
+func TestDomainLimiterOverrides(t *testing.T) {
+	l := NewDomainLimiter(5, 2, DefaultDomainLimitOverrides)
+
+	if got := l.limiter(Domain("alice.bsky.social")).Limit(); got != 20 {
+		t.Errorf("bsky.social limit = %v, want the override of 20", got)
+	}
+	if got := l.limiter(Domain("alice.example.com")).Limit(); got != 5 {
+		t.Errorf("example.com limit = %v, want the default of 5", got)
+	}
+
+	// New limits apply to the limiters that already exist
+	existing := l.limiter("example.com")
+	l.SetLimits(2, 4, map[string]rate.Limit{"example.com": 50})
+	if existing.Limit() != 50 || existing.Burst() != 4 {
+		t.Errorf("example.com after SetLimits = %v/%d, want 50/4", existing.Limit(), existing.Burst())
+	}
+	if got := l.limiter("bsky.social").Limit(); got != 2 {
+		t.Errorf("bsky.social after its override was removed = %v, want 2", got)
+	}
+}
+
+func TestDomainLimiterEviction(t *testing.T) {
+	l := NewDomainLimiter(5, 1, nil)
+	l.IdleTTL = time.Minute
+
+	idle := l.limiter("example.com")
+	busy := l.limiter("example.net")
+
+	// Age example.com past IdleTTL and make the next call sweep
+	l.limiters["example.com"].lastUsed = time.Now().Add(-2 * time.Minute)
+	l.lastSweep = time.Now().Add(-2 * time.Minute)
+
+	if l.limiter("example.net") != busy {
+		t.Error("a limiter in use was evicted")
+	}
+	if _, ok := l.limiters["example.com"]; ok {
+		t.Fatal("an idle limiter wasn't evicted")
+	}
+
+	recreated := l.limiter("example.com")
+	if recreated == idle {
+		t.Error("an evicted limiter was reused")
+	}
+	if recreated.Limit() != 5 || recreated.Tokens() < 1 {
+		t.Errorf("recreated limiter = %v with %f tokens, want 5 with a full burst", recreated.Limit(), recreated.Tokens())
+	}
+}
+
+func TestParseDomainLimits(t *testing.T) {
+	limits, err := ParseDomainLimits([]string{"bsky.social=50", "pds.Example.com=2.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits["bsky.social"] != 50 || limits["example.com"] != 2.5 || len(limits) != 2 {
+		t.Errorf("ParseDomainLimits = %v", limits)
+	}
+
+	for _, bad := range []string{"bsky.social", "bsky.social=fast"} {
+		if _, err := ParseDomainLimits([]string{bad}); err == nil {
+			t.Errorf("ParseDomainLimits accepted %q", bad)
+		}
+	}
+}
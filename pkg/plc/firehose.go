@@ -0,0 +1,351 @@
+package plc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
+)
+
+var firehoseEventsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "firehose_events_total",
+	Help: "Total number of identity and account events received from the firehose",
+}, []string{"type"})
+
+// Firehose consumes identity and account events from a relay's subscribeRepos stream
+// and revalidates the affected DIDs right away instead of waiting for their next scheduled check.
+// Replicas share the stream through a lock in redis, only the replica holding it consumes events.
+type Firehose struct {
+	Endpoint    string
+	Directory   *Directory
+	Logger      *zap.SugaredLogger
+	Concurrency int64
+	Cursor      int64
+
+	ReconnectBackoff    time.Duration
+	MaxReconnectBackoff time.Duration
+
+	// LockTTL is how long the consumer lock outlives a replica that stopped renewing it
+	LockTTL time.Duration
+
+	// handle is called for each identity, handle and account event
+	handle func(ctx context.Context, evtType string, evt *firehoseEvent)
+}
+
+type firehoseHeader struct {
+	Op   int64  `cbor:"op"`
+	Type string `cbor:"t"`
+}
+
+// firehoseEvent holds the fields of #identity, #handle and #account events we use,
+// other event types are only decoded far enough to track the sequence number
+type firehoseEvent struct {
+	Seq    int64  `cbor:"seq"`
+	Did    string `cbor:"did"`
+	Handle string `cbor:"handle"`
+	Active *bool  `cbor:"active"`
+}
+
+type firehoseError struct {
+	Error   string `cbor:"error"`
+	Message string `cbor:"message"`
+}
+
+func NewFirehose(endpoint string, directory *Directory) *Firehose {
+	f := &Firehose{
+		Endpoint:    endpoint,
+		Directory:   directory,
+		Logger:      directory.Logger.With("source", "firehose"),
+		Concurrency: 10,
+
+		ReconnectBackoff:    time.Second,
+		MaxReconnectBackoff: time.Minute,
+
+		LockTTL: 30 * time.Second,
+	}
+	f.handle = f.handleEvent
+
+	return f
+}
+
+func (f *Firehose) lockKey() string {
+	return f.Directory.RedisPrefix + ":firehose_lock"
+}
+
+// renewLockScript extends the lock only if this replica still holds it
+var renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLockScript deletes the lock only if this replica still holds it
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Run waits for the firehose lock and consumes the firehose while holding it, until ctx is cancelled.
+// Replicas that don't hold the lock retry periodically and take over once the holder lets it lapse.
+func (f *Firehose) Run(ctx context.Context) error {
+	d := f.Directory
+
+	for {
+		held, err := d.RedisClient.SetNX(ctx, f.lockKey(), d.WorkerID, f.LockTTL).Result()
+		if err != nil && ctx.Err() == nil {
+			f.Logger.Errorf("failed to acquire firehose lock: %+v", err)
+		}
+
+		if held {
+			f.Logger.Infow("acquired firehose lock", "worker", d.WorkerID)
+
+			// The previous holder's cursor is where we pick up
+			f.loadCursor(ctx)
+
+			lockCtx, cancel := context.WithCancel(ctx)
+			go f.renewLock(lockCtx, cancel)
+			err := f.run(lockCtx)
+			cancel()
+
+			if err := releaseLockScript.Run(context.Background(), d.RedisClient, []string{f.lockKey()}, d.WorkerID).Err(); err != nil {
+				f.Logger.Errorf("failed to release firehose lock: %+v", err)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(f.LockTTL / 3):
+		}
+	}
+}
+
+// renewLock keeps the firehose lock alive until ctx is cancelled, calling lost if it can't be renewed
+func (f *Firehose) renewLock(ctx context.Context, lost context.CancelFunc) {
+	d := f.Directory
+	ticker := time.NewTicker(f.LockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := renewLockScript.Run(ctx, d.RedisClient, []string{f.lockKey()}, d.WorkerID, f.LockTTL.Milliseconds()).Int()
+		if err != nil && ctx.Err() == nil {
+			// Keep trying until the lock would have lapsed
+			f.Logger.Errorf("failed to renew firehose lock: %+v", err)
+			continue
+		}
+		if renewed == 0 && ctx.Err() == nil {
+			f.Logger.Info("lost firehose lock, stopping consumer")
+			lost()
+			return
+		}
+	}
+}
+
+// loadCursor resumes from the last saved cursor, or the live stream if there isn't one
+func (f *Firehose) loadCursor(ctx context.Context) {
+	d := f.Directory
+	val, err := d.RedisClient.Get(ctx, d.RedisPrefix+":firehose_cursor").Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			f.Logger.Errorf("failed to get firehose cursor: %+v", err)
+		}
+		return
+	}
+
+	f.Cursor, err = strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		f.Logger.Info("failed to parse firehose cursor, starting from the live stream")
+	}
+}
+
+// run consumes the firehose until ctx is cancelled, reconnecting with backoff on failure.
+// Events already being handled when ctx is cancelled are allowed to finish before the cursor
+// is flushed and run returns
+func (f *Firehose) run(ctx context.Context) error {
+	// In-flight revalidations run on their own context so a shutdown doesn't abort them halfway
+	work := context.Background()
+	sem := semaphore.NewWeighted(f.Concurrency)
+
+	backoff := f.ReconnectBackoff
+	for {
+		connected, err := f.consume(ctx, work, sem)
+		if err != nil && ctx.Err() == nil {
+			f.Logger.Errorf("firehose connection failed: %+v", err)
+		}
+		if connected {
+			backoff = f.ReconnectBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			// Wait for in-flight handlers before persisting the cursor
+			if err := sem.Acquire(work, f.Concurrency); err != nil {
+				return err
+			}
+			f.saveCursor(work)
+			f.Logger.Info("firehose stopped")
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > f.MaxReconnectBackoff {
+			backoff = f.MaxReconnectBackoff
+		}
+	}
+}
+
+// consume reads events from a single firehose connection until it fails, reporting whether it connected
+func (f *Firehose) consume(ctx, work context.Context, sem *semaphore.Weighted) (bool, error) {
+	u, err := url.Parse(f.Endpoint)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse firehose endpoint: %w", err)
+	}
+	if f.Cursor > 0 {
+		q := u.Query()
+		q.Set("cursor", strconv.FormatInt(f.Cursor, 10))
+		u.RawQuery = q.Encode()
+	}
+
+	f.Logger.Infof("connecting to firehose at %s", u.String())
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to dial firehose: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock ReadMessage when we're asked to stop
+	closed := make(chan struct{})
+	defer close(closed)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-closed:
+		}
+	}()
+
+	lastSaved := time.Now()
+
+	for {
+		msgType, msg, err := conn.ReadMessage()
+		if err != nil {
+			return true, fmt.Errorf("failed to read firehose message: %w", err)
+		}
+		if msgType != websocket.BinaryMessage {
+			continue
+		}
+
+		header, evt, err := decodeFirehoseFrame(msg)
+		if err != nil {
+			return true, err
+		}
+
+		if evt.Seq > 0 {
+			f.Cursor = evt.Seq
+		}
+
+		switch header.Type {
+		case "#identity", "#handle", "#account":
+			firehoseEventsCounter.WithLabelValues(header.Type).Inc()
+			if evt.Did == "" {
+				continue
+			}
+			if err := sem.Acquire(ctx, 1); err != nil {
+				return true, err
+			}
+			go func(evtType string, evt *firehoseEvent) {
+				defer sem.Release(1)
+				f.handle(work, evtType, evt)
+			}(header.Type, evt)
+		}
+
+		if time.Since(lastSaved) > 5*time.Second {
+			f.saveCursor(work)
+			lastSaved = time.Now()
+		}
+	}
+}
+
+// decodeFirehoseFrame splits a firehose message into its CBOR header and body
+func decodeFirehoseFrame(msg []byte) (*firehoseHeader, *firehoseEvent, error) {
+	dec := cbor.NewDecoder(bytes.NewReader(msg))
+
+	header := &firehoseHeader{}
+	if err := dec.Decode(header); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode firehose header: %w", err)
+	}
+
+	if header.Op == -1 {
+		errFrame := &firehoseError{}
+		if err := dec.Decode(errFrame); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode firehose error frame: %w", err)
+		}
+		return nil, nil, fmt.Errorf("firehose error %s: %s", errFrame.Error, errFrame.Message)
+	}
+
+	evt := &firehoseEvent{}
+	if err := dec.Decode(evt); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode firehose event: %w", err)
+	}
+
+	return header, evt, nil
+}
+
+func (f *Firehose) handleEvent(ctx context.Context, evtType string, evt *firehoseEvent) {
+	// Events with a handle are validated against it if the DID document claims it, and the DID is tracked
+	// from now on if it's new. Otherwise, like account events without a handle, they revalidate whatever we have stored.
+	entry, err := f.Directory.Revalidate(ctx, evt.Did, evt.Handle)
+	if errors.Is(err, store.ErrNotFound) {
+		// A DID we don't track with no handle to check, the PLC export picks it up if it claims one
+		return
+	}
+	if err != nil {
+		f.Logger.Errorw("failed to revalidate DID from firehose event",
+			"type", evtType,
+			"did", evt.Did,
+			"error", err,
+		)
+		return
+	}
+
+	f.Logger.Infow("revalidated DID from firehose event",
+		"type", evtType,
+		"did", entry.Did,
+		"handle", entry.Handle,
+		"valid", entry.IsValid,
+	)
+}
+
+func (f *Firehose) saveCursor(ctx context.Context) {
+	d := f.Directory
+	err := d.RedisClient.Set(ctx, d.RedisPrefix+":firehose_cursor", strconv.FormatInt(f.Cursor, 10), 0).Err()
+	if err != nil {
+		f.Logger.Errorf("failed to set firehose cursor: %+v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package plc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// firehoseFrame encodes a header and body the way a relay frames subscribeRepos messages
+func firehoseFrame(t *testing.T, header, body any) []byte {
+	t.Helper()
+	h, err := cbor.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := cbor.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(h, b...)
+}
+
+func TestDecodeFirehoseFrame(t *testing.T) {
+	frame := firehoseFrame(t,
+		map[string]any{"op": 1, "t": "#identity"},
+		map[string]any{"seq": 42, "did": "did:plc:ewvi7nxzyoun6zhxrhs64oiz", "handle": "alice.test", "time": "2023-09-01T00:00:00Z"},
+	)
+	header, evt, err := decodeFirehoseFrame(frame)
+	if err != nil {
+		t.Fatalf("decodeFirehoseFrame: %v", err)
+	}
+	if header.Op != 1 || header.Type != "#identity" {
+		t.Errorf("header = %+v, want op 1 #identity", header)
+	}
+	if evt.Seq != 42 || evt.Did != "did:plc:ewvi7nxzyoun6zhxrhs64oiz" || evt.Handle != "alice.test" || evt.Active != nil {
+		t.Errorf("event = %+v", evt)
+	}
+
+	active := false
+	frame = firehoseFrame(t,
+		map[string]any{"op": 1, "t": "#account"},
+		map[string]any{"seq": 43, "did": "did:plc:ewvi7nxzyoun6zhxrhs64oiz", "active": active},
+	)
+	_, evt, err = decodeFirehoseFrame(frame)
+	if err != nil {
+		t.Fatalf("decodeFirehoseFrame: %v", err)
+	}
+	if evt.Active == nil || *evt.Active {
+		t.Errorf("account event active = %v, want false", evt.Active)
+	}
+
+	frame = firehoseFrame(t,
+		map[string]any{"op": -1},
+		map[string]any{"error": "FutureCursor", "message": "cursor in the future"},
+	)
+	if _, _, err := decodeFirehoseFrame(frame); err == nil || !strings.Contains(err.Error(), "FutureCursor") {
+		t.Errorf("error frame err = %v, want FutureCursor", err)
+	}
+
+	if _, _, err := decodeFirehoseFrame([]byte{0xff, 0x00}); err == nil {
+		t.Error("decoding garbage succeeded")
+	}
+}
+
+func TestFirehoseReconnectResumesCursor(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+
+	var lk sync.Mutex
+	var cursors []string
+	connections := 0
+
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lk.Lock()
+		connections++
+		n := connections
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		lk.Unlock()
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		send := func(evtType string, body map[string]any) {
+			frame := firehoseFrame(t, map[string]any{"op": 1, "t": evtType}, body)
+			if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+				t.Errorf("write: %v", err)
+			}
+		}
+
+		switch n {
+		case 1:
+			send("#identity", map[string]any{"seq": 1, "did": "did:plc:a", "handle": "a.test"})
+			send("#commit", map[string]any{"seq": 2, "repo": "did:plc:a"})
+			send("#account", map[string]any{"seq": 3, "did": "did:plc:b", "active": true})
+			// Drop the connection, the consumer should come back from seq 3
+		default:
+			send("#handle", map[string]any{"seq": 4, "did": "did:plc:c", "handle": "c.test"})
+			// Hold the connection open until the consumer goes away
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	defer relay.Close()
+
+	handled := make(chan *firehoseEvent, 10)
+	f := &Firehose{
+		Endpoint: "ws" + strings.TrimPrefix(relay.URL, "http"),
+		Directory: &Directory{
+			// Nothing listens here, saving the cursor on shutdown just fails
+			RedisClient: redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1}),
+		},
+		Logger:      zap.NewNop().Sugar(),
+		Concurrency: 2,
+
+		ReconnectBackoff:    10 * time.Millisecond,
+		MaxReconnectBackoff: 10 * time.Millisecond,
+	}
+	f.handle = func(ctx context.Context, evtType string, evt *firehoseEvent) {
+		handled <- evt
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- f.run(ctx)
+	}()
+
+	got := map[string]bool{}
+	for len(got) < 3 {
+		select {
+		case evt := <-handled:
+			got[evt.Did] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+	for _, did := range []string{"did:plc:a", "did:plc:b", "did:plc:c"} {
+		if !got[did] {
+			t.Errorf("event for %s wasn't handled", did)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run didn't stop after cancel")
+	}
+
+	if f.Cursor != 4 {
+		t.Errorf("cursor = %d, want 4", f.Cursor)
+	}
+
+	lk.Lock()
+	defer lk.Unlock()
+	if len(cursors) < 2 || cursors[0] != "" || cursors[1] != "3" {
+		t.Errorf("connected with cursors %q, want the second connection to resume from 3", cursors)
+	}
+}
@@ -0,0 +1,120 @@
+package plc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ImportFile loads a local PLC export snapshot (plain, gzip or zstd compressed JSONL)
+// through the same path as live ingestion and moves the cursor to the last imported operation
+func (d *Directory) ImportFile(ctx context.Context, path string) error {
+	ctx, span := tracer.Start(ctx, "ImportFile")
+	defer span.End()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open export file: %w", err)
+	}
+	defer f.Close()
+
+	r, err := decompressExport(bufio.NewReaderSize(f, 1024*1024))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	start := time.Now()
+	var count int
+
+	// Reading the file feeds the same batch writer live ingestion uses
+	batches := make(chan []*DirectoryJSONLRow, d.PipelineDepth)
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		// A snapshot already holds the recovery operation that follows every nullified one,
+		// so there's nothing to roll back
+		return d.writeBatches(ctx, batches, false)
+	})
+	eg.Go(func() error {
+		defer close(batches)
+
+		batch := make([]*DirectoryJSONLRow, 0, d.BatchSize)
+		send := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			batch = make([]*DirectoryJSONLRow, 0, d.BatchSize)
+			return nil
+		}
+
+		err := decodeDirectoryEntries(r, func(entry *DirectoryJSONLRow) error {
+			count++
+			batch = append(batch, entry)
+			if len(batch) >= d.BatchSize {
+				return send()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return send()
+	})
+
+	if err := eg.Wait(); err != nil {
+		return fmt.Errorf("failed to import export file after %d entries: %w", count, err)
+	}
+
+	d.Logger.Infow("finished importing directory entries",
+		"imported", count,
+		"cursor", d.AfterCursor.String(),
+		"elapsed", time.Since(start).Seconds(),
+	)
+
+	return nil
+}
+
+// decompressExport sniffs the compression format of an export and returns a reader for the plain JSONL
+func decompressExport(r *bufio.Reader) (io.ReadCloser, error) {
+	header, err := r.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read export header: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gz, nil
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
@@ -0,0 +1,472 @@
+package plc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/ericvolp12/bingo/pkg/syntax"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrExportWedged means more operations share the cursor's millisecond than the upstream returns in a page.
+// The export can only be paged by timestamp, so ingestion can't get past them without skipping operations.
+var ErrExportWedged = errors.New("export page is entirely at the cursor's timestamp")
+
+var exportWedgedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "plc_export_wedged",
+	Help: "1 while PLC ingestion is stuck on a millisecond with more operations than fit in an export page",
+})
+
+// exportPage is a raw page of the PLC export and the upstream that served it
+type exportPage struct {
+	body     []byte
+	upstream *Upstream
+}
+
+// fetchDirectoryEntries catches up on the PLC export through a fetch -> parse -> write pipeline.
+// Pages are fetched ahead while earlier ones are parsed and written in batches,
+// and the cursor only moves once a batch has been written.
+// Cancelling stop ends fetching new pages, but pages already fetched are still written.
+func (d *Directory) fetchDirectoryEntries(stop context.Context, ctx context.Context) {
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+	d.Logger.Info("fetching directory entries...")
+
+	pages := make(chan exportPage, d.PipelineDepth)
+	batches := make(chan []*DirectoryJSONLRow, d.PipelineDepth)
+
+	var wedged error
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		defer close(pages)
+		err := d.fetchPages(stop, ctx, client, pages)
+		// Pages fetched before the export wedged are still written
+		if errors.Is(err, ErrExportWedged) {
+			wedged = err
+			return nil
+		}
+		return err
+	})
+	eg.Go(func() error {
+		defer close(batches)
+		return d.parsePages(ctx, pages, batches)
+	})
+	eg.Go(func() error {
+		return d.writeBatches(ctx, batches, true)
+	})
+
+	if err := eg.Wait(); err != nil {
+		d.Logger.Errorf("failed to fetch directory entries: %+v", err)
+	}
+	if wedged != nil {
+		exportWedgedGauge.Set(1)
+		d.Logger.Errorf("failed to fetch directory entries: %+v", wedged)
+	} else {
+		exportWedgedGauge.Set(0)
+	}
+
+	d.Logger.Info("finished fetching directory entries")
+}
+
+const (
+	// exportPageSize is the number of operations requested per page of the export
+	exportPageSize = 1000
+	// maxExportPageSize caps how far the page size is raised to get past a millisecond with more operations than fit
+	// in a page, for upstreams that serve bigger pages than plc.directory, which caps them at 1000
+	maxExportPageSize = 16000
+)
+
+// fetchPages pages through the export from the cursor until it stops making progress or stop is cancelled
+func (d *Directory) fetchPages(stop context.Context, ctx context.Context, client *http.Client, pages chan<- exportPage) error {
+	cursor := d.AfterCursor.Clone()
+	var lastCid string
+	if len(cursor.Cids) > 0 {
+		lastCid = cursor.Cids[len(cursor.Cids)-1]
+	}
+
+	count := exportPageSize
+
+	for {
+		if stop.Err() != nil {
+			return nil
+		}
+
+		after := cursor.After()
+
+		d.Logger.Infof("querying for entries after %s", cursor.String())
+		resp, upstream, err := d.Upstreams.Do(ctx, client, func(u *Upstream) string {
+			exportURL, err := url.Parse(u.Endpoint)
+			if err != nil {
+				return u.Endpoint
+			}
+			q := exportURL.Query()
+			q.Set("count", strconv.Itoa(count))
+			if !after.IsZero() {
+				q.Add("after", after.Format(time.RFC3339Nano))
+			}
+			exportURL.RawQuery = q.Encode()
+			return exportURL.String()
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("failed to fetch directory entries from %s: %s", upstream.Endpoint, resp.Status)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			upstream.Observe(false)
+			return fmt.Errorf("failed to read directory entries from %s: %w", upstream.Endpoint, err)
+		}
+
+		first, last, rows, err := pageBounds(body)
+		if err != nil {
+			upstream.Observe(false)
+			return fmt.Errorf("failed to read directory entries from %s: %w", upstream.Endpoint, err)
+		}
+
+		if last == nil {
+			return nil
+		}
+
+		// A page that ends where the last one did means we're caught up,
+		// unless it's a full page entirely at the cursor's millisecond. Then the rest of that
+		// millisecond doesn't fit in a page and the same page would come back forever.
+		if last.Cid == lastCid {
+			wedged := rows >= exportPageSize &&
+				first.CreatedAt.Equal(cursor.CreatedAt) &&
+				last.CreatedAt.Equal(cursor.CreatedAt)
+			if !wedged {
+				return nil
+			}
+
+			if rows >= count && count < maxExportPageSize {
+				count *= 2
+				if count > maxExportPageSize {
+					count = maxExportPageSize
+				}
+				d.Logger.Warnw("export page is entirely at the cursor's timestamp, raising the page size",
+					"cursor", cursor.String(),
+					"count", count,
+				)
+				continue
+			}
+
+			// The upstream won't return a bigger page. Skipping past the millisecond would drop the operations
+			// that didn't fit, so stop here and try again next time, an upstream may serve bigger pages by then.
+			return fmt.Errorf("%w: more than %d operations at %s from %s",
+				ErrExportWedged, rows, cursor.CreatedAt.Format(time.RFC3339Nano), upstream.Endpoint)
+		}
+		lastCid = last.Cid
+		cursor.CreatedAt = last.CreatedAt
+		count = exportPageSize
+
+		select {
+		case pages <- exportPage{body: body, upstream: upstream}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pageBounds decodes only the first and last rows of a page of the export, along with how many rows it holds
+func pageBounds(body []byte) (*DirectoryJSONLRow, *DirectoryJSONLRow, int, error) {
+	body = bytes.TrimRight(body, "\n")
+	if len(body) == 0 {
+		return nil, nil, 0, nil
+	}
+
+	decodeRow := func(line []byte) (*DirectoryJSONLRow, error) {
+		var entry DirectoryJSONLRow
+		if err := decodeDirectoryEntries(bytes.NewReader(line), func(row *DirectoryJSONLRow) error {
+			entry = *row
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return &entry, nil
+	}
+
+	firstLine := body
+	if i := bytes.IndexByte(body, '\n'); i >= 0 {
+		firstLine = body[:i]
+	}
+	first, err := decodeRow(firstLine)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	last, err := decodeRow(body[bytes.LastIndexByte(body, '\n')+1:])
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return first, last, bytes.Count(body, []byte("\n")) + 1, nil
+}
+
+// parsePages decodes pages into batches of operations that haven't been applied yet
+func (d *Directory) parsePages(ctx context.Context, pages <-chan exportPage, batches chan<- []*DirectoryJSONLRow) error {
+	cursor := d.AfterCursor.Clone()
+	batch := make([]*DirectoryJSONLRow, 0, d.BatchSize)
+
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		batch = make([]*DirectoryJSONLRow, 0, d.BatchSize)
+		return nil
+	}
+
+	for page := range pages {
+		err := decodeDirectoryEntries(bytes.NewReader(page.body), func(entry *DirectoryJSONLRow) error {
+			// Pages overlap the cursor's timestamp, skip anything already applied
+			if cursor.Seen(entry) {
+				return nil
+			}
+			cursor.Advance(entry)
+
+			batch = append(batch, entry)
+			if len(batch) >= d.BatchSize {
+				return send()
+			}
+			return nil
+		})
+		if err != nil {
+			page.upstream.Observe(false)
+			return fmt.Errorf("failed to read directory entries from %s: %w", page.upstream.Endpoint, err)
+		}
+	}
+
+	return send()
+}
+
+// writeBatches applies batches to the store in order, committing the cursor alongside each one.
+// rollback controls whether DIDs left on a nullified operation are rolled back, see applyBatch.
+func (d *Directory) writeBatches(ctx context.Context, batches <-chan []*DirectoryJSONLRow, rollback bool) error {
+	for batch := range batches {
+		start := time.Now()
+
+		cursor := d.AfterCursor.Clone()
+		for _, entry := range batch {
+			cursor.Advance(entry)
+		}
+
+		if err := d.applyBatch(ctx, batch, cursor, rollback); err != nil {
+			return fmt.Errorf("failed to write directory entries: %w", err)
+		}
+
+		d.AfterCursor = cursor
+		d.cacheCursor(ctx)
+
+		d.Logger.Infow("wrote directory entries",
+			"entries", len(batch),
+			"cursor", d.AfterCursor.String(),
+			"write_time", time.Since(start).Seconds(),
+		)
+	}
+
+	return nil
+}
+
+// applyBatch records a batch of operations in the operation log and applies the new ones to the store,
+// committing cursor in the same transaction so postgres never holds entries the cursor hasn't reached.
+// Only the latest state of each DID in the batch is written, using bulk upserts and pipelined redis writes.
+// If rollback is set, DIDs whose last operation in the batch was nullified are rolled back to their
+// last surviving operation in the local operation log, within the same transaction.
+func (d *Directory) applyBatch(ctx context.Context, batch []*DirectoryJSONLRow, cursor Cursor, rollback bool) error {
+	ctx, span := tracer.Start(ctx, "applyBatch")
+	defer span.End()
+	span.SetAttributes(attribute.Int("entries", len(batch)))
+
+	cursorVal, err := json.Marshal(cursor)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	tx, err := d.Store.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// A CID can only be upserted once per statement, keep the latest copy
+	opsByCid := map[string]*store.Operation{}
+	ops := make([]*store.Operation, 0, len(batch))
+	for _, entry := range batch {
+		op := &store.Operation{
+			Cid:       entry.Cid,
+			Did:       entry.Did,
+			Handle:    entry.Operation.Handle(),
+			Operation: entry.RawOperation,
+			Nullified: entry.Nullified,
+			CreatedAt: entry.CreatedAt,
+		}
+		if existing, ok := opsByCid[entry.Cid]; ok {
+			*existing = *op
+			continue
+		}
+		opsByCid[entry.Cid] = op
+		ops = append(ops, op)
+	}
+
+	changed, err := tx.RecordOperations(ctx, ops)
+	if err != nil {
+		return err
+	}
+
+	var dids []string
+	seen := map[string]bool{}
+	docs := map[string]*store.DIDDocument{}
+	entries := map[string]*store.Entry{}
+	tombstoned := map[string]bool{}
+	nullified := map[string]bool{}
+
+	// apply moves a DID to the state described by an operation
+	apply := func(did string, op *Operation) {
+		if op.Type == opTypeTombstone {
+			tombstoned[did] = true
+			delete(docs, did)
+			delete(entries, did)
+			return
+		}
+		delete(tombstoned, did)
+		docs[did] = op.Document(did)
+		if handle := op.Handle(); handle != "" {
+			entries[did] = &store.Entry{
+				Did:     did,
+				Handle:  handle,
+				IsValid: false,
+			}
+		}
+	}
+
+	for _, entry := range batch {
+		// Skip operations that have already been applied
+		if !changed[entry.Cid] {
+			continue
+		}
+
+		if err := syntax.ValidateDID(entry.Did); err != nil {
+			d.Logger.Errorf("skipping operation %s: %+v", entry.Cid, err)
+			continue
+		}
+
+		if d.AuditOperations {
+			d.auditOperation(ctx, tx, entry)
+		}
+
+		if !seen[entry.Did] {
+			seen[entry.Did] = true
+			dids = append(dids, entry.Did)
+		}
+
+		// Nullified operations were forked away by a recovery operation, which comes later in the export.
+		// If the recovery operation isn't in this batch, the DID is rolled back to its last surviving operation.
+		if entry.Nullified {
+			nullified[entry.Did] = true
+			continue
+		}
+		delete(nullified, entry.Did)
+		apply(entry.Did, &entry.Operation)
+	}
+
+	for _, did := range dids {
+		if !rollback || !nullified[did] {
+			continue
+		}
+
+		last, err := lastSurvivingOperation(ctx, tx, did)
+		if err != nil {
+			return fmt.Errorf("failed to roll back nullified operations for %s: %w", did, err)
+		}
+		if last == nil {
+			// Nothing survived the fork, drop the DID
+			apply(did, &Operation{Type: opTypeTombstone})
+			continue
+		}
+		apply(did, last)
+	}
+
+	var docList []*store.DIDDocument
+	var entryList []*store.Entry
+	for _, did := range dids {
+		if tombstoned[did] {
+			if err := tx.Delete(ctx, did); err != nil {
+				return err
+			}
+			continue
+		}
+		if doc, ok := docs[did]; ok {
+			docList = append(docList, doc)
+		}
+		if entry, ok := entries[did]; ok {
+			entryList = append(entryList, entry)
+		}
+	}
+
+	if err := tx.UpdateDIDDocuments(ctx, docList); err != nil {
+		return err
+	}
+
+	if err := tx.BulkUpdate(ctx, entryList); err != nil {
+		return err
+	}
+
+	if err := tx.SetCursor(ctx, cursorName, string(cursorVal)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		if !errors.Is(err, store.ErrCacheWrite) {
+			return err
+		}
+		// The batch is committed, lookups for these DIDs fall back to stale cache entries until they're rewritten
+		d.Logger.Errorf("failed to cache directory entries: %+v", err)
+	}
+
+	return nil
+}
+
+// lastSurvivingOperation returns the latest non-nullified operation in a DID's local operation log, or nil if there isn't one
+func lastSurvivingOperation(ctx context.Context, tx *store.Tx, did string) (*Operation, error) {
+	ops, err := tx.GetOperationsForDID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(ops) - 1; i >= 0; i-- {
+		if ops[i].Nullified {
+			continue
+		}
+		var op Operation
+		if err := json.Unmarshal(ops[i].Operation, &op); err != nil {
+			return nil, fmt.Errorf("failed to decode operation %s: %w", ops[i].Cid, err)
+		}
+		return &op, nil
+	}
+
+	return nil, nil
+}
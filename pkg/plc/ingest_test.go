@@ -0,0 +1,188 @@
+package plc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// exportRow encodes a row of the PLC export
+func exportRow(t *testing.T, cid string, createdAt time.Time) []byte {
+	t.Helper()
+	row, err := json.Marshal(map[string]any{
+		"did":       testDID,
+		"operation": map[string]any{"type": "plc_operation"},
+		"cid":       cid,
+		"nullified": false,
+		"createdAt": createdAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(row, '\n')
+}
+
+func TestPageBounds(t *testing.T) {
+	at := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	first, last, rows, err := pageBounds(nil)
+	if err != nil || first != nil || last != nil || rows != 0 {
+		t.Errorf("pageBounds of an empty page = %v, %v, %d, %v", first, last, rows, err)
+	}
+
+	var body []byte
+	for i := 0; i < 3; i++ {
+		body = append(body, exportRow(t, fmt.Sprintf("cid-%d", i), at.Add(time.Duration(i)*time.Millisecond))...)
+	}
+	first, last, rows, err = pageBounds(body)
+	if err != nil {
+		t.Fatalf("pageBounds: %v", err)
+	}
+	if first.Cid != "cid-0" || last.Cid != "cid-2" || rows != 3 {
+		t.Errorf("pageBounds = %s, %s, %d, want cid-0, cid-2, 3", first.Cid, last.Cid, rows)
+	}
+	if !last.CreatedAt.Equal(at.Add(2 * time.Millisecond)) {
+		t.Errorf("last row createdAt = %s", last.CreatedAt)
+	}
+
+	// A single row without a trailing newline is both the first and the last
+	first, last, rows, err = pageBounds(bytes.TrimSpace(exportRow(t, "only", at)))
+	if err != nil || first.Cid != "only" || last.Cid != "only" || rows != 1 {
+		t.Errorf("pageBounds of one row = %v, %v, %d, %v", first, last, rows, err)
+	}
+
+	if _, _, _, err := pageBounds([]byte("{\"cid\": \"a\"}\nnot json\n")); err == nil {
+		t.Error("pageBounds accepted a malformed last row")
+	}
+}
+
+// newTestExport serves rows through an export endpoint that pages by `after` and returns at most maxCount rows
+func newTestExport(t *testing.T, rows []*DirectoryJSONLRow, maxCount int) *UpstreamPool {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		if count == 0 || count > maxCount {
+			count = maxCount
+		}
+		var after time.Time
+		if v := r.URL.Query().Get("after"); v != "" {
+			var err error
+			if after, err = time.Parse(time.RFC3339Nano, v); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		for _, row := range rows {
+			if count == 0 {
+				break
+			}
+			if row.CreatedAt.After(after) {
+				w.Write(exportRow(t, row.Cid, row.CreatedAt))
+				count--
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	pool, err := NewUpstreamPool([]string{srv.URL + "/export"}, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// runFetchPages fetches pages from the cursor and returns the rows of every page fetched
+func runFetchPages(t *testing.T, pool *UpstreamPool, cursor Cursor) ([][]*DirectoryJSONLRow, error) {
+	t.Helper()
+
+	d := &Directory{
+		Upstreams:   pool,
+		Logger:      zap.NewNop().Sugar(),
+		AfterCursor: cursor,
+	}
+
+	pages := make(chan exportPage, 100)
+	err := d.fetchPages(context.Background(), context.Background(), http.DefaultClient, pages)
+	close(pages)
+
+	var fetched [][]*DirectoryJSONLRow
+	for page := range pages {
+		var rows []*DirectoryJSONLRow
+		if err := decodeDirectoryEntries(bytes.NewReader(page.body), func(row *DirectoryJSONLRow) error {
+			rows = append(rows, row)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		fetched = append(fetched, rows)
+	}
+	return fetched, err
+}
+
+// busyMillisecond returns n rows at one millisecond followed by one row a millisecond later
+func busyMillisecond(at time.Time, n int) []*DirectoryJSONLRow {
+	var rows []*DirectoryJSONLRow
+	for i := 0; i < n; i++ {
+		rows = append(rows, &DirectoryJSONLRow{Cid: fmt.Sprintf("cid-%04d", i), CreatedAt: at})
+	}
+	return append(rows, &DirectoryJSONLRow{Cid: "cid-next", CreatedAt: at.Add(time.Millisecond)})
+}
+
+// cursorAfter returns the cursor left after applying rows
+func cursorAfter(rows []*DirectoryJSONLRow) Cursor {
+	var c Cursor
+	for _, row := range rows {
+		c.Advance(row)
+	}
+	return c
+}
+
+func TestFetchPagesRaisesPageSize(t *testing.T) {
+	at := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	rows := busyMillisecond(at, 1500)
+
+	// The first full page at the busy millisecond has been applied, the rest of it doesn't fit in a page
+	pages, err := runFetchPages(t, newTestExport(t, rows, maxExportPageSize), cursorAfter(rows[:exportPageSize]))
+	if err != nil {
+		t.Fatalf("fetchPages: %v", err)
+	}
+
+	// A bigger page covers the whole millisecond and gets past it
+	if len(pages) != 1 || len(pages[0]) != len(rows) {
+		t.Fatalf("fetched %d pages, want one page with all %d rows", len(pages), len(rows))
+	}
+	if last := pages[0][len(pages[0])-1]; last.Cid != "cid-next" {
+		t.Errorf("last row = %s, want cid-next", last.Cid)
+	}
+}
+
+func TestFetchPagesStopsWhenWedged(t *testing.T) {
+	at := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	rows := busyMillisecond(at, 1500)
+
+	// Like plc.directory, the upstream never returns more than a page
+	pages, err := runFetchPages(t, newTestExport(t, rows, exportPageSize), cursorAfter(rows[:exportPageSize]))
+	if !errors.Is(err, ErrExportWedged) {
+		t.Fatalf("fetchPages = %v, want ErrExportWedged", err)
+	}
+
+	// Nothing past the millisecond is fetched, the operations that didn't fit would be lost
+	for _, page := range pages {
+		for _, row := range page {
+			if row.Cid == "cid-next" {
+				t.Fatal("fetchPages skipped past operations it couldn't fetch")
+			}
+		}
+	}
+}
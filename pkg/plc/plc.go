@@ -2,19 +2,28 @@ package plc
 
 import (
 	"bufio"
+	"bytes"
 	"context"
-	"database/sql"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/ericvolp12/bingo/pkg/didweb"
+	"github.com/ericvolp12/bingo/pkg/fetcher"
+	"github.com/ericvolp12/bingo/pkg/resolver"
 	"github.com/ericvolp12/bingo/pkg/store"
 	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+	"github.com/ericvolp12/bingo/pkg/syntax"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/redis/go-redis/v9"
@@ -23,28 +32,48 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"golang.org/x/sync/semaphore"
-	"golang.org/x/time/rate"
 )
 
 var plcDirectoryRequestHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Name: "plc_directory_request_duration_seconds",
 	Help: "Histogram of the time (in seconds) each request to the PLC directory takes",
-}, []string{"status_code"})
+}, []string{"endpoint", "status_code"})
 
 type Directory struct {
-	Endpoint       string
-	PLCRateLimiter *rate.Limiter
-	PDSRateLimiter *rate.Limiter
-	CheckPeriod    time.Duration
-	AfterCursor    time.Time
-	Logger         *zap.SugaredLogger
+	Upstreams   *UpstreamPool
+	AfterCursor Cursor
+	Logger      *zap.SugaredLogger
 
-	ValidationTTL time.Duration
+	// DomainLimiter limits HTTPS validation requests per registrable domain
+	DomainLimiter *DomainLimiter
+
+	// Resolver looks up _atproto TXT records during handle validation
+	Resolver resolver.Resolver
+
+	// Fetcher makes the requests to hosts named by handles and did:web DIDs
+	Fetcher *fetcher.Fetcher
+
+	// WorkerID identifies this replica's leases on the entries it's validating
+	WorkerID string
+
+	// AuditOperations enables local verification of each ingested operation's signature and prev linkage
+	AuditOperations bool
+
+	// BatchSize is the number of operations written to the store at once during ingestion
+	BatchSize int
+	// PipelineDepth is the number of pages and batches buffered between ingestion stages
+	PipelineDepth int
 
 	RedisClient *redis.Client
 	RedisPrefix string
 
 	Store *store.Store
+
+	lk         sync.Mutex
+	settings   Settings
+	done       chan struct{}
+	cancelStop context.CancelFunc
+	cancelWork context.CancelFunc
 }
 
 type DirectoryEntry struct {
@@ -57,21 +86,146 @@ type RawDirectoryEntry struct {
 }
 
 type DirectoryJSONLRow struct {
-	Did       string    `json:"did"`
-	Operation Operation `json:"operation"`
-	Cid       string    `json:"cid"`
-	Nullified bool      `json:"nullified"`
-	CreatedAt time.Time `json:"createdAt"`
+	Did          string          `json:"did"`
+	Operation    Operation       `json:"operation"`
+	RawOperation json.RawMessage `json:"-"`
+	Cid          string          `json:"cid"`
+	Nullified    bool            `json:"nullified"`
+	CreatedAt    time.Time       `json:"createdAt"`
+}
+
+// UnmarshalJSON decodes the row while keeping a copy of the raw operation for the operation log
+func (r *DirectoryJSONLRow) UnmarshalJSON(data []byte) error {
+	type row DirectoryJSONLRow
+	aux := struct {
+		*row
+		Operation json.RawMessage `json:"operation"`
+	}{row: (*row)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.RawOperation = aux.Operation
+	if len(aux.Operation) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(aux.Operation, &r.Operation)
 }
 
 type Operation struct {
-	AlsoKnownAs []string `json:"alsoKnownAs"`
-	Type        string   `json:"type"`
+	AlsoKnownAs         []string                    `json:"alsoKnownAs"`
+	Type                string                      `json:"type"`
+	VerificationMethods map[string]string           `json:"verificationMethods"`
+	RotationKeys        []string                    `json:"rotationKeys"`
+	Services            map[string]OperationService `json:"services"`
+}
+
+type OperationService struct {
+	Type     string `json:"type"`
+	Endpoint string `json:"endpoint"`
+}
+
+const (
+	opTypeTombstone = "plc_tombstone"
+	opTypeCreate    = "create"
+)
+
+// cursorName identifies the PLC export cursor in the cursors table
+const cursorName = "plc_directory"
+
+// legacyCreateOperation is the original PLC genesis format, which predates alsoKnownAs and services
+type legacyCreateOperation struct {
+	SigningKey  string `json:"signingKey"`
+	RecoveryKey string `json:"recoveryKey"`
+	Handle      string `json:"handle"`
+	Service     string `json:"service"`
+}
+
+// UnmarshalJSON decodes both current and legacy create operations into the current operation format
+func (op *Operation) UnmarshalJSON(data []byte) error {
+	type operation Operation
+	*op = Operation{}
+	if err := json.Unmarshal(data, (*operation)(op)); err != nil {
+		return err
+	}
+
+	if op.Type != opTypeCreate {
+		return nil
+	}
+
+	var legacy legacyCreateOperation
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	// Normalize the same way the PLC directory does when it serves legacy operations
+	op.VerificationMethods = map[string]string{"atproto": legacy.SigningKey}
+	op.RotationKeys = []string{legacy.RecoveryKey, legacy.SigningKey}
+	op.AlsoKnownAs = nil
+	if legacy.Handle != "" {
+		op.AlsoKnownAs = []string{"at://" + legacy.Handle}
+	}
+	op.Services = nil
+	if legacy.Service != "" {
+		op.Services = map[string]OperationService{
+			"atproto_pds": {
+				Type:     "AtprotoPersonalDataServer",
+				Endpoint: legacy.Service,
+			},
+		}
+	}
+
+	return nil
+}
+
+// Handle returns the normalized handle claimed by the operation or an empty string if it doesn't claim a valid one
+func (op *Operation) Handle() string {
+	if len(op.AlsoKnownAs) == 0 {
+		return ""
+	}
+	handle, err := syntax.NormalizeHandle(strings.TrimPrefix(op.AlsoKnownAs[0], "at://"))
+	if err != nil {
+		return ""
+	}
+	return handle
+}
+
+// Document returns the DID document state described by the operation
+func (op *Operation) Document(did string) *store.DIDDocument {
+	services := map[string]store.Service{}
+	for id, svc := range op.Services {
+		services[id] = store.Service{
+			Type:     svc.Type,
+			Endpoint: svc.Endpoint,
+		}
+	}
+
+	return &store.DIDDocument{
+		Did:                 did,
+		AlsoKnownAs:         op.AlsoKnownAs,
+		VerificationMethods: op.VerificationMethods,
+		RotationKeys:        op.RotationKeys,
+		Services:            services,
+	}
 }
 
 var tracer = otel.Tracer("plc-directory")
 
-func NewDirectory(endpoint string, redisClient *redis.Client, store *store.Store, redisPrefix string) (*Directory, error) {
+// newWorkerID names this process for leasing, the hostname tells operators which replica holds a lease
+// and the random suffix keeps restarts and replicas sharing a hostname apart
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "bingo"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return hostname + "-" + hex.EncodeToString(suffix)
+}
+
+func NewDirectory(endpoints []string, redisClient *redis.Client, st *store.Store, redisPrefix string) (*Directory, error) {
 	ctx := context.Background()
 	rawLogger, err := zap.NewProduction()
 	if err != nil {
@@ -79,136 +233,298 @@ func NewDirectory(endpoint string, redisClient *redis.Client, store *store.Store
 	}
 	logger := rawLogger.Sugar().With("source", "plc_directory")
 
-	cmd := redisClient.Get(ctx, redisPrefix+":last_cursor")
-	if cmd.Err() != nil {
+	// Postgres holds the cursor committed with the last batch, redis is only consulted
+	// for deployments that haven't committed a batch since the cursor moved to postgres
+	cursorVal, err := st.GetCursor(ctx, cursorName)
+	if errors.Is(err, store.ErrNotFound) {
+		cursorVal, err = redisClient.Get(ctx, redisPrefix+":last_cursor").Result()
+	}
+	if err != nil {
 		logger.Info("no last cursor found, starting from beginning")
 	}
 
-	var lastCursor time.Time
-	if cmd.Val() != "" {
-		lastCursor, err = time.Parse(time.RFC3339Nano, cmd.Val())
+	var lastCursor Cursor
+	if cursorVal != "" {
+		lastCursor, err = parseCursor(cursorVal)
 		if err != nil {
 			logger.Info("failed to parse last cursor, starting from beginning")
 		}
 	}
 
+	upstreams, err := NewUpstreamPool(endpoints, 2)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Directory{
-		Endpoint:       endpoint,
-		Logger:         logger,
-		PLCRateLimiter: rate.NewLimiter(rate.Limit(2), 1),
-		PDSRateLimiter: rate.NewLimiter(rate.Limit(20), 1),
-		CheckPeriod:    30 * time.Second,
-		AfterCursor:    lastCursor,
+		Upstreams:   upstreams,
+		Logger:      logger,
+		AfterCursor: lastCursor,
+
+		DomainLimiter: NewDomainLimiter(DefaultDomainLimit, 1, DefaultDomainLimitOverrides),
 
-		ValidationTTL: 12 * time.Hour,
+		Resolver: net.DefaultResolver,
+		Fetcher:  fetcher.New(),
+
+		WorkerID: newWorkerID(),
+
+		BatchSize:     1000,
+		PipelineDepth: 4,
 
 		RedisClient: redisClient,
 		RedisPrefix: redisPrefix,
 
-		Store: store,
+		Store: st,
+
+		settings: DefaultSettings,
 	}, nil
 }
 
-func (d *Directory) Start() {
-	ticker := time.NewTicker(d.CheckPeriod)
-	ctx := context.Background()
+// Run ingests the PLC export and validates handles until ctx is cancelled or Stop is called.
+// Once stopped, in-flight ingestion and validation pages are allowed to finish and the cursor is flushed before Run returns.
+func (d *Directory) Run(ctx context.Context) error {
+	d.lk.Lock()
+	if d.done != nil {
+		d.lk.Unlock()
+		return fmt.Errorf("directory is already running")
+	}
+	stop, cancelStop := context.WithCancel(ctx)
+	// In-flight work isn't tied to ctx so it can drain, it's only cut short if Stop's deadline passes
+	work, cancelWork := context.WithCancel(context.Background())
+	d.cancelStop = cancelStop
+	d.cancelWork = cancelWork
+	d.done = make(chan struct{})
+	done := d.done
+	d.lk.Unlock()
+
+	defer close(done)
+	defer cancelWork()
+	defer cancelStop()
+
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
 	go func() {
-		d.fetchDirectoryEntries(ctx)
+		defer wg.Done()
+		runEvery(stop, d.checkPeriod, func() {
+			d.fetchDirectoryEntries(stop, work)
+		})
+	}()
 
-		for range ticker.C {
-			d.fetchDirectoryEntries(ctx)
-		}
+	// Only worth probing when there's something to fail over to
+	if len(d.Upstreams.Upstreams) > 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport), Timeout: 30 * time.Second}
+			runEvery(stop, d.checkPeriod, func() {
+				d.Upstreams.CheckHealth(work, client)
+			})
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.validateHandles(stop, work, 0, 5*time.Second)
 	}()
 
+	wg.Add(1)
 	go func() {
-		d.ValidateHandles(ctx, 1200, 5*time.Second)
+		defer wg.Done()
+		d.runRevalidationQueue(stop, work)
 	}()
+
+	wg.Wait()
+
+	// Refresh the cached cursor in case the last cache write failed, the committed one is already in postgres
+	d.cacheCursor(context.Background())
+
+	d.Logger.Info("directory stopped")
+
+	return nil
 }
 
-func (d *Directory) fetchDirectoryEntries(ctx context.Context) {
-	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+// Stop signals Run to stop and waits for in-flight work to drain.
+// If ctx expires first, in-flight work is cancelled and Stop returns ctx's error once Run has returned.
+func (d *Directory) Stop(ctx context.Context) error {
+	d.lk.Lock()
+	done := d.done
+	cancelStop := d.cancelStop
+	cancelWork := d.cancelWork
+	d.lk.Unlock()
+
+	if done == nil {
+		return nil
+	}
 
-	d.Logger.Info("fetching directory entries...")
+	d.Logger.Info("stopping directory, waiting for in-flight work to drain...")
+	cancelStop()
 
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cancelWork()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// checkPeriod returns how often validation pages are started, as currently configured
+func (d *Directory) checkPeriod() time.Duration {
+	return d.Settings().CheckPeriod
+}
+
+// runEvery calls fn every period until stop is cancelled, the period is read again after each call
+// so a reconfigured period applies from the next tick
+func runEvery(stop context.Context, period func() time.Duration, fn func()) {
 	for {
-		d.Logger.Infof("querying for entries after %s", d.AfterCursor.Format(time.RFC3339Nano))
-		req, err := http.NewRequestWithContext(ctx, "GET", d.Endpoint, nil)
-		if err != nil {
-			d.Logger.Errorf("failed to create request: %+v", err)
-			break
-		}
-		q := req.URL.Query()
-		if !d.AfterCursor.IsZero() {
-			q.Add("after", d.AfterCursor.Format(time.RFC3339Nano))
-		}
-		req.URL.RawQuery = q.Encode()
-		d.PLCRateLimiter.Wait(ctx)
 		start := time.Now()
-		resp, err := client.Do(req)
-		plcDirectoryRequestHistogram.WithLabelValues(fmt.Sprintf("%d", resp.StatusCode)).Observe(time.Since(start).Seconds())
-		if err != nil {
-			d.Logger.Errorf("failed to fetch directory entries: %+v", err)
-			resp.Body.Close()
-			break
+		fn()
+
+		timer := time.NewTimer(time.Until(start.Add(period())))
+		select {
+		case <-stop.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
 		}
+	}
+}
 
-		// Create a bufio scanner to read the response line by line
-		scanner := bufio.NewScanner(resp.Body)
+// decodeDirectoryEntries reads JSONL rows from a PLC export and calls fn for each row in order
+func decodeDirectoryEntries(r io.Reader, fn func(entry *DirectoryJSONLRow) error) error {
+	// Create a bufio scanner to read the export line by line
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
-		var newEntries []DirectoryJSONLRow
-		for scanner.Scan() {
-			line := scanner.Text()
-			var entry DirectoryJSONLRow
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
 
-			// Try to unmarshal the line into a DirectoryJSONLRow
-			if err := json.Unmarshal([]byte(line), &entry); err != nil {
-				d.Logger.Errorf("failed to unmarshal directory entry: %+v", err)
-				resp.Body.Close()
-				return
-			}
+		var entry DirectoryJSONLRow
+		// Try to unmarshal the line into a DirectoryJSONLRow
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("failed to unmarshal directory entry: %w", err)
+		}
 
-			newEntries = append(newEntries, entry)
+		if err := fn(&entry); err != nil {
+			return err
 		}
+	}
 
-		// Check if the scan finished without errors
-		if err := scanner.Err(); err != nil {
-			d.Logger.Errorf("failed to read response body: %+v", err)
-			resp.Body.Close()
-			return
+	// Check if the scan finished without errors
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read directory entries: %w", err)
+	}
+
+	return nil
+}
+
+// cacheCursor mirrors the committed AfterCursor to redis, postgres remains the source of truth
+func (d *Directory) cacheCursor(ctx context.Context) {
+	val, err := json.Marshal(d.AfterCursor)
+	if err != nil {
+		d.Logger.Errorf("failed to marshal last cursor: %+v", err)
+		return
+	}
+
+	cmd := d.RedisClient.Set(ctx, d.RedisPrefix+":last_cursor", val, 0)
+	if cmd.Err() != nil {
+		d.Logger.Errorf("failed to set last cursor: %+v", cmd.Err())
+	}
+}
+
+// lookupTXT resolves a handle's _atproto TXT records, along with how long the answer stays cached if the resolver knows
+func (d *Directory) lookupTXT(ctx context.Context, handle string) ([]string, time.Duration, error) {
+	name := fmt.Sprintf("_atproto.%s", handle)
+	if r, ok := d.Resolver.(resolver.TTLResolver); ok {
+		return r.LookupTXTWithTTL(ctx, name)
+	}
+
+	txts, err := d.Resolver.LookupTXT(ctx, name)
+	return txts, 0, err
+}
+
+// errDocumentUnavailable is returned when a DID's document isn't known locally yet,
+// usually because ingestion hasn't reached its operations
+var errDocumentUnavailable = errors.New("DID document isn't available yet")
+
+// documentClaimsHandle reports whether the DID document's alsoKnownAs claims the handle.
+// The stored document can lag behind events like the firehose's, so if it's missing or doesn't
+// claim the handle, it's rebuilt from its source and stored.
+func (d *Directory) documentClaimsHandle(ctx context.Context, did string, handle string) (bool, error) {
+	doc, err := d.Store.GetDIDDocument(ctx, did)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return false, err
+	}
+	if doc != nil && strings.EqualFold(doc.Handle(), handle) {
+		return true, nil
+	}
+
+	doc, err = d.loadDIDDocument(ctx, did)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(doc.Handle(), handle), nil
+}
+
+// loadDIDDocument rebuilds the current DID document and stores it, from the did:web host or,
+// for did:plc, from the local operation log. Validation never asks the PLC directory,
+// its rate limit is reserved for ingestion, which brings in new operations soon enough.
+func (d *Directory) loadDIDDocument(ctx context.Context, did string) (*store.DIDDocument, error) {
+	ctx, span := tracer.Start(ctx, "loadDIDDocument")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	if didweb.IsDIDWeb(did) {
+		webDoc, err := didweb.Resolve(ctx, d.Fetcher.Client, did)
+		if err != nil {
+			return nil, err
+		}
+		doc := didWebDocument(webDoc)
+		if err := d.Store.UpdateDIDDocument(ctx, doc); err != nil {
+			return nil, err
 		}
+		return doc, nil
+	}
+
+	ops, err := d.Store.GetOperationsForDID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
 
-		if len(newEntries) <= 1 {
-			resp.Body.Close()
+	var last *store.Operation
+	for i := len(ops) - 1; i >= 0; i-- {
+		if !ops[i].Nullified {
+			last = ops[i]
 			break
 		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("%w: no operations for %s in the local log", errDocumentUnavailable, did)
+	}
 
-		resp.Body.Close()
-
-		for _, entry := range newEntries {
-			if len(entry.Operation.AlsoKnownAs) > 0 {
-				handle := strings.TrimPrefix(entry.Operation.AlsoKnownAs[0], "at://")
-				if handle != "" && len(handle) < 254 {
-					err := d.Store.Update(ctx, &store.Entry{
-						Did:     entry.Did,
-						Handle:  handle,
-						IsValid: false,
-					})
-					if err != nil {
-						d.Logger.Errorf("failed to update entry: %+v", err)
-					}
-				}
-			}
-		}
+	var op Operation
+	if err := json.Unmarshal(last.Operation, &op); err != nil {
+		return nil, fmt.Errorf("failed to decode operation %s: %w", last.Cid, err)
+	}
 
-		d.AfterCursor = newEntries[len(newEntries)-1].CreatedAt
-		cmd := d.RedisClient.Set(ctx, d.RedisPrefix+":last_cursor", d.AfterCursor.Format(time.RFC3339Nano), 0)
-		if cmd.Err() != nil {
-			d.Logger.Errorf("failed to set last cursor: %+v", cmd.Err())
-		}
-		d.Logger.Infof("fetched %d new directory entries", len(newEntries))
+	// A tombstoned DID claims nothing, there's no document to store
+	doc := op.Document(did)
+	if op.Type == opTypeTombstone {
+		return doc, nil
+	}
+
+	if err := d.Store.UpdateDIDDocument(ctx, doc); err != nil {
+		return nil, err
 	}
 
-	d.Logger.Info("finished fetching directory entries")
+	return doc, nil
 }
 
 var plcDirectoryValidationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
@@ -217,15 +533,28 @@ var plcDirectoryValidationHistogram = promauto.NewHistogramVec(prometheus.Histog
 }, []string{"is_valid"})
 
 func (d *Directory) ValidateHandles(ctx context.Context, pageSize int, timeBetweenLoops time.Duration) {
+	d.validateHandles(ctx, ctx, pageSize, timeBetweenLoops)
+}
+
+// validateHandles validates pages with the work context until stop is cancelled, always finishing the current page.
+// A pageSize of 0 follows the configured ValidationPageSize.
+func (d *Directory) validateHandles(stop context.Context, work context.Context, pageSize int, timeBetweenLoops time.Duration) {
 	logger := d.Logger.With("source", "plc_directory_validation")
 	for {
 		select {
-		case <-ctx.Done():
+		case <-stop.Done():
 			logger.Info("context cancelled, stopping validation loop")
 			return
 		default:
-			if !d.ValidateHandlePage(ctx, pageSize) {
-				time.Sleep(timeBetweenLoops)
+			size := pageSize
+			if size == 0 {
+				size = d.Settings().ValidationPageSize
+			}
+			if !d.ValidateHandlePage(work, size) {
+				select {
+				case <-stop.Done():
+				case <-time.After(timeBetweenLoops):
+				}
 			}
 		}
 	}
@@ -235,27 +564,36 @@ func (d *Directory) ValidateHandlePage(ctx context.Context, pageSize int) bool {
 	ctx, span := tracer.Start(ctx, "ValidateHandles")
 	defer span.End()
 
-	logger := d.Logger.With("source", "validate_handle_page")
+	logger := d.Logger.With("source", "validate_handle_page", "worker", d.WorkerID)
 
 	logger.Info("validating handles entries...")
 
 	start := time.Now()
 
-	entries, err := d.Store.Queries.GetEntriesForValidation(ctx, store_queries.GetEntriesForValidationParams{
-		LastCheckedTime: sql.NullTime{Time: time.Now().Add(-d.ValidationTTL), Valid: true},
-		Limit:           int32(pageSize),
+	settings := d.Settings()
+
+	// Leasing the page keeps other replicas from validating the same entries, the lease is renewed while
+	// the page runs and released when the results are written, or expires if this worker dies first
+	entries, err := d.Store.Queries.ClaimEntriesForValidation(ctx, store_queries.ClaimEntriesForValidationParams{
+		LeaseOwner:   d.WorkerID,
+		LeaseSeconds: settings.ValidationLease.Seconds(),
+		Limit:        int32(pageSize),
 	})
 	if err != nil {
-		logger.Errorf("failed to get entries for validation: %+v", err)
+		logger.Errorf("failed to claim entries for validation: %+v", err)
 		return false
 	}
 
 	queryDone := time.Now()
 
-	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	dids := make([]string, len(entries))
+	for i, entry := range entries {
+		dids[i] = entry.Did
+	}
+	leaseCtx, stopRenewing := context.WithCancel(ctx)
+	go d.renewLeases(leaseCtx, logger, dids, settings.ValidationLease)
 
-	// Validate in 20 goroutines
-	sem := semaphore.NewWeighted(20)
+	sem := semaphore.NewWeighted(int64(settings.ValidationConcurrency))
 	wg := &sync.WaitGroup{}
 
 	storeEntries := []*store.Entry{}
@@ -264,19 +602,39 @@ func (d *Directory) ValidateHandlePage(ctx context.Context, pageSize int) bool {
 	numValid := atomic.Int64{}
 	numInvalid := atomic.Int64{}
 
-	for _, entry := range entries {
+	// Entries left unscheduled when ctx is cancelled, their leases are released once the page is written
+	var unscheduled []string
+
+	for i, entry := range entries {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			for _, entry := range entries[i:] {
+				unscheduled = append(unscheduled, entry.Did)
+			}
+			break
+		}
 		wg.Add(1)
 		go func(entry store_queries.Entry) {
 			defer wg.Done()
 			defer sem.Release(1)
 			validStart := time.Now()
-			valid, errs := d.ValidateHandle(ctx, client, entry.Did, entry.Handle)
+			prev := &store.Entry{
+				Handle:          entry.Handle,
+				IsValid:         entry.IsValid,
+				LastCheckedTime: entry.LastCheckedTime.Time,
+				CheckStreak:     int(entry.CheckStreak),
+			}
+			if didweb.IsDIDWeb(entry.Did) {
+				entry.Handle = d.refreshDIDWebHandle(ctx, entry.Did, entry.Handle)
+			}
+			validation := d.ValidateHandle(ctx, entry.Did, entry.Handle)
+			valid := validation.Valid()
 			plcDirectoryValidationHistogram.WithLabelValues(fmt.Sprintf("%t", valid)).Observe(time.Since(validStart).Seconds())
-			if len(errs) != 0 {
+			if len(validation.Errors) != 0 {
 				logger.Errorw("failed to validate handle",
 					"did", entry.Did,
 					"handle", entry.Handle,
-					"errors", errs,
+					"status", validation.Status,
+					"errors", validation.Errors,
 				)
 			}
 			if valid {
@@ -285,28 +643,35 @@ func (d *Directory) ValidateHandlePage(ctx context.Context, pageSize int) bool {
 				numInvalid.Add(1)
 			}
 
+			storeEntry := validation.Entry(&settings.Schedule, prev, entry.Did, entry.Handle)
+
 			lk.Lock()
-			storeEntries = append(storeEntries, &store.Entry{
-				Did:             entry.Did,
-				Handle:          entry.Handle,
-				IsValid:         valid,
-				LastCheckedTime: time.Now(),
-			})
+			storeEntries = append(storeEntries, storeEntry)
 			lk.Unlock()
 		}(entry)
-		sem.Acquire(ctx, 1)
 	}
 
 	wg.Wait()
+	stopRenewing()
 
 	validationDone := time.Now()
 
-	// Update the entries in the database
-	err = d.Store.BulkUpdateEntryValidation(ctx, storeEntries)
+	// Update the entries in the database, those whose lease was lost to another replica are skipped
+	err = d.Store.BulkUpdateEntryValidation(ctx, d.WorkerID, storeEntries)
 	if err != nil {
 		logger.Errorf("failed to update entries: %+v", err)
 	}
 
+	if len(unscheduled) > 0 {
+		// ctx is already cancelled, give the release a moment of its own
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := d.Store.ReleaseEntryLeases(releaseCtx, d.WorkerID, unscheduled)
+		cancel()
+		if err != nil {
+			logger.Errorf("failed to release leases of unscheduled entries: %+v", err)
+		}
+	}
+
 	updateDone := time.Now()
 
 	logger.Infow("finished validating directory entries",
@@ -325,58 +690,101 @@ func (d *Directory) ValidateHandlePage(ctx context.Context, pageSize int) bool {
 	return false
 }
 
-func (d *Directory) ValidateHandle(ctx context.Context, client *http.Client, did string, handle string) (bool, []error) {
+// renewLeases keeps this replica's validation leases on a page's entries alive until ctx is cancelled,
+// so a page that runs longer than the lease isn't claimed by another replica halfway through
+func (d *Directory) renewLeases(ctx context.Context, logger *zap.SugaredLogger, dids []string, lease time.Duration) {
+	ticker := time.NewTicker(lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep trying until the leases would have lapsed
+		if err := d.Store.RenewEntryLeases(ctx, d.WorkerID, dids, lease); err != nil && ctx.Err() == nil {
+			logger.Errorf("failed to renew validation leases: %+v", err)
+		}
+	}
+}
+
+func (d *Directory) ValidateHandle(ctx context.Context, did string, handle string) *Validation {
 	ctx, span := tracer.Start(ctx, "ValidateHandle")
 	defer span.End()
 
-	var errs []error
+	// Validity runs both ways, the DID document has to claim the handle before we check the handle points back.
+	// Otherwise a domain that still points at a DID after it moved to a new handle would stay valid.
+	claimed, err := d.documentClaimsHandle(ctx, did, handle)
+	if err != nil {
+		return newValidation(store.ValidationStatusDocumentError, fmt.Errorf("failed to check DID document for handle: %+v", err))
+	}
+	if !claimed {
+		span.SetAttributes(attribute.Bool("unclaimed", true))
+		return newValidation(store.ValidationStatusUnclaimed, fmt.Errorf("DID document doesn't claim handle %s", handle))
+	}
+
+	return d.validateHandleRecords(ctx, did, handle)
+}
+
+// validateHandleRecords checks that a handle points back at the DID, through its _atproto TXT record
+// or its /.well-known/atproto-did
+func (d *Directory) validateHandleRecords(ctx context.Context, did string, handle string) (validation *Validation) {
+	ctx, span := tracer.Start(ctx, "validateHandleRecords")
+	defer span.End()
+
+	// Every outcome after the TXT lookup carries its TTL, so the next check isn't scheduled before it expires
+	var ttl time.Duration
+	defer func() {
+		validation.DNSTTL = ttl
+	}()
+
 	expectedTxtValue := fmt.Sprintf("did=%s", did)
 
 	// Start by looking up TXT records for the Handle
-	txtrecords, err := net.DefaultResolver.LookupTXT(ctx, fmt.Sprintf("_atproto.%s", handle))
+	var txtFailure *Validation
+	txtrecords, ttl, err := d.lookupTXT(ctx, handle)
 	if err != nil {
-		errs = append(errs, fmt.Errorf("failed to lookup TXT records for handle: %+v", err))
+		txtFailure = newValidation(dnsErrorStatus(err), fmt.Errorf("failed to lookup TXT records for handle: %+v", err))
 	} else {
 		for _, txt := range txtrecords {
 			if txt == expectedTxtValue {
 				span.SetAttributes(attribute.Bool("txt_validated", true))
-				return true, nil
+				return newValidation(store.ValidationStatusValid)
 			}
 		}
+		txtFailure = newValidation(store.ValidationStatusTXTMismatch, fmt.Errorf("TXT records for handle don't match %q: %q", expectedTxtValue, txtrecords))
 	}
 
-	// If no TXT records were found, check /.well-known/atproto-did
-	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://%s/.well-known/atproto-did", handle), nil)
-	if err != nil {
-		return false, append(errs, fmt.Errorf("failed to create request for HTTPS validation: %+v", err))
-	}
-
-	// If the handle ends in `.bsky.social`, use the PDS rate limiter
-	if strings.HasSuffix(handle, ".bsky.social") {
-		d.PDSRateLimiter.Wait(ctx)
+	// If no TXT records were found, check /.well-known/atproto-did,
+	// sharing the request budget with every other handle under the same registrable domain
+	if err := d.DomainLimiter.Wait(ctx, handle); err != nil {
+		return txtFailure.and(newValidation(httpErrorStatus(err), fmt.Errorf("rate limited fetching /.well-known/atproto-did: %+v", err)))
 	}
 
-	resp, err := client.Do(req)
+	// The fetcher refuses internal addresses, redirects and oversized bodies since anyone can point a handle anywhere
+	resp, err := d.Fetcher.Get(ctx, fmt.Sprintf("https://%s/.well-known/atproto-did", handle))
 	if err != nil {
-		return false, append(errs, fmt.Errorf("failed to fetch /.well-known/atproto-did: %+v", err))
+		return txtFailure.and(newValidation(httpErrorStatus(err), fmt.Errorf("failed to fetch /.well-known/atproto-did: %+v", err)))
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		span.SetAttributes(attribute.Bool("both_invalid", true))
 		span.SetAttributes(attribute.Int("https_status_code", resp.StatusCode))
-		return false, append(errs, fmt.Errorf("failed to fetch /.well-known/atproto-did: %s", resp.Status))
+		return txtFailure.and(newValidation(store.ValidationStatusHTTPStatus, fmt.Errorf("failed to fetch /.well-known/atproto-did: %s", resp.Status)))
 	}
 
 	// There should only be one line in the response with the contenr of the DID
-	scanner := bufio.NewScanner(resp.Body)
+	scanner := bufio.NewScanner(bytes.NewReader(resp.Body))
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line == did {
 			span.SetAttributes(attribute.Bool("https_validated", true))
-			return true, nil
+			return newValidation(store.ValidationStatusValid)
 		}
 	}
 
 	span.SetAttributes(attribute.Bool("both_invalid", true))
-	return false, append(errs, fmt.Errorf("failed to find DID in /.well-known/atproto-did"))
+	return txtFailure.and(newValidation(store.ValidationStatusBodyMismatch, fmt.Errorf("failed to find DID in /.well-known/atproto-did")))
 }
@@ -0,0 +1,283 @@
+package plc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var revalidationQueueCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "revalidation_queue_total",
+	Help: "Total number of DIDs enqueued and processed through the on-demand revalidation queue",
+}, []string{"event"})
+
+// Priorities for on-demand revalidation, higher priorities are validated first
+const (
+	// PriorityStale is for entries that were served stale by Lookup
+	PriorityStale  = 0
+	PriorityBatch  = 1
+	PrioritySingle = 2
+)
+
+// revalidationJobTTL is how long jobs and their results are kept around for polling
+const revalidationJobTTL = time.Hour
+
+// priorityScoreSpan separates priorities in the queue, it's larger than any millisecond timestamp
+// so every DID of a higher priority is popped before any of a lower one, and FIFO within a priority
+const priorityScoreSpan = 1e13
+
+var ErrJobNotFound = errors.New("revalidation job not found")
+
+// RevalidationJob tracks a set of DIDs queued for revalidation together
+type RevalidationJob struct {
+	ID      string
+	Results map[string]*RevalidationResult
+}
+
+// RevalidationResult is the outcome of revalidating one identifier, nil in a job's Results while pending
+type RevalidationResult struct {
+	Entry *store.Entry `json:"entry,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// Done reports whether every identifier in the job has a result
+func (job *RevalidationJob) Done() bool {
+	for _, result := range job.Results {
+		if result == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *Directory) revalidationQueueKey() string {
+	return d.RedisPrefix + ":revalidation_queue"
+}
+
+func (d *Directory) revalidationJobKey(id string) string {
+	return d.RedisPrefix + ":revalidation_job:" + id
+}
+
+// revalidationWaitersKey holds the IDs of the jobs waiting on a DID
+func (d *Directory) revalidationWaitersKey(did string) string {
+	return d.RedisPrefix + ":revalidation_waiters:" + did
+}
+
+// EnqueueRevalidation puts DIDs at the front of the validation queue and returns the ID of a job tracking them.
+// Identifiers that already failed, like handles that couldn't be resolved, are recorded in the job as they are.
+// A DID that's already queued keeps its place unless the new priority is higher.
+func (d *Directory) EnqueueRevalidation(ctx context.Context, dids []string, failed map[string]*RevalidationResult, priority int) (string, error) {
+	ctx, span := tracer.Start(ctx, "EnqueueRevalidation")
+	defer span.End()
+	span.SetAttributes(attribute.Int("dids", len(dids)))
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	id := hex.EncodeToString(idBytes)
+	jobKey := d.revalidationJobKey(id)
+
+	fields := make([]interface{}, 0, 2*(len(dids)+len(failed)))
+	for _, did := range dids {
+		fields = append(fields, did, "")
+	}
+	for identifier, result := range failed {
+		val, err := json.Marshal(result)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal revalidation result: %w", err)
+		}
+		fields = append(fields, identifier, string(val))
+	}
+	if len(fields) == 0 {
+		return "", fmt.Errorf("no identifiers to revalidate")
+	}
+
+	members := queueMembers(dids, priority)
+
+	// Waiters are registered before the DID is queued so a worker can never miss them
+	pipeline := d.RedisClient.TxPipeline()
+	pipeline.HSet(ctx, jobKey, fields...)
+	pipeline.Expire(ctx, jobKey, revalidationJobTTL)
+	for _, did := range dids {
+		pipeline.SAdd(ctx, d.revalidationWaitersKey(did), id)
+		pipeline.Expire(ctx, d.revalidationWaitersKey(did), revalidationJobTTL)
+	}
+	if len(members) > 0 {
+		pipeline.ZAddArgs(ctx, d.revalidationQueueKey(), redis.ZAddArgs{LT: true, Members: members})
+	}
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to enqueue revalidation: %w", err)
+	}
+
+	revalidationQueueCounter.WithLabelValues("enqueued").Add(float64(len(dids)))
+
+	return id, nil
+}
+
+// QueueRevalidation puts DIDs in the validation queue without tracking them in a job.
+// DIDs that are already queued aren't queued twice, they only move up if the new priority is higher.
+func (d *Directory) QueueRevalidation(ctx context.Context, dids []string, priority int) error {
+	ctx, span := tracer.Start(ctx, "QueueRevalidation")
+	defer span.End()
+	span.SetAttributes(attribute.Int("dids", len(dids)))
+
+	if len(dids) == 0 {
+		return nil
+	}
+
+	err := d.RedisClient.ZAddArgs(ctx, d.revalidationQueueKey(), redis.ZAddArgs{LT: true, Members: queueMembers(dids, priority)}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to queue revalidation: %w", err)
+	}
+
+	revalidationQueueCounter.WithLabelValues("enqueued").Add(float64(len(dids)))
+
+	return nil
+}
+
+// queueMembers scores DIDs for the queue, by priority and then by when they were queued
+func queueMembers(dids []string, priority int) []redis.Z {
+	score := float64(time.Now().UnixMilli()) - float64(priority)*priorityScoreSpan
+	members := make([]redis.Z, 0, len(dids))
+	for _, did := range dids {
+		members = append(members, redis.Z{Score: score, Member: did})
+	}
+	return members
+}
+
+// GetRevalidationJob returns a job and the results it has so far
+func (d *Directory) GetRevalidationJob(ctx context.Context, id string) (*RevalidationJob, error) {
+	ctx, span := tracer.Start(ctx, "GetRevalidationJob")
+	defer span.End()
+	span.SetAttributes(attribute.String("id", id))
+
+	fields, err := d.RedisClient.HGetAll(ctx, d.revalidationJobKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revalidation job: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	job := &RevalidationJob{ID: id, Results: make(map[string]*RevalidationResult, len(fields))}
+	for identifier, val := range fields {
+		if val == "" {
+			job.Results[identifier] = nil
+			continue
+		}
+		result := &RevalidationResult{}
+		if err := json.Unmarshal([]byte(val), result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal revalidation result: %w", err)
+		}
+		job.Results[identifier] = result
+	}
+
+	return job, nil
+}
+
+// runRevalidationQueue validates queued DIDs as they arrive until stop is cancelled.
+// It runs alongside the scheduled pages so on-demand checks never wait behind a page,
+// with up to RevalidationConcurrency of them in flight as currently configured.
+func (d *Directory) runRevalidationQueue(stop context.Context, work context.Context) {
+	logger := d.Logger.With("source", "revalidation_queue")
+
+	var wg sync.WaitGroup
+	var inFlight atomic.Int64
+	// finished wakes the loop when a revalidation frees up a slot
+	finished := make(chan struct{}, 1)
+
+	for stop.Err() == nil {
+		if inFlight.Load() >= int64(d.Settings().RevalidationConcurrency) {
+			select {
+			case <-stop.Done():
+			case <-finished:
+			}
+			continue
+		}
+
+		// Block briefly so stop is noticed even when the queue is idle
+		popped, err := d.RedisClient.BZPopMin(stop, time.Second, d.revalidationQueueKey()).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && stop.Err() == nil {
+				logger.Errorf("failed to pop revalidation queue: %+v", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		did, _ := popped.Member.(string)
+		inFlight.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				inFlight.Add(-1)
+				select {
+				case finished <- struct{}{}:
+				default:
+				}
+			}()
+			d.processRevalidation(work, did)
+		}()
+	}
+
+	// Wait for in-flight revalidations to finish
+	wg.Wait()
+	logger.Info("context cancelled, stopping revalidation queue")
+}
+
+// processRevalidation validates a queued DID and records the result in every job waiting on it
+func (d *Directory) processRevalidation(ctx context.Context, did string) {
+	ctx, span := tracer.Start(ctx, "processRevalidation")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	result := &RevalidationResult{}
+	entry, err := d.Revalidate(ctx, did, "")
+	if err != nil {
+		result.Error = err.Error()
+		if errors.Is(err, store.ErrNotFound) {
+			result.Error = "DID not found"
+		}
+	} else {
+		result.Entry = entry
+	}
+	revalidationQueueCounter.WithLabelValues("processed").Inc()
+
+	val, err := json.Marshal(result)
+	if err != nil {
+		d.Logger.Errorf("failed to marshal revalidation result: %+v", err)
+		return
+	}
+
+	// Claim the waiters atomically, any job registering after this has queued the DID again and is answered by that check
+	pipeline := d.RedisClient.TxPipeline()
+	waitersCmd := pipeline.SMembers(ctx, d.revalidationWaitersKey(did))
+	pipeline.Del(ctx, d.revalidationWaitersKey(did))
+	if _, err := pipeline.Exec(ctx); err != nil {
+		d.Logger.Errorf("failed to claim revalidation waiters: %+v", err)
+		return
+	}
+
+	pipeline = d.RedisClient.Pipeline()
+	for _, id := range waitersCmd.Val() {
+		pipeline.HSet(ctx, d.revalidationJobKey(id), did, string(val))
+		pipeline.Expire(ctx, d.revalidationJobKey(id), revalidationJobTTL)
+	}
+	if _, err := pipeline.Exec(ctx); err != nil {
+		d.Logger.Errorf("failed to record revalidation result: %+v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package plc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/redistest"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"go.uber.org/zap"
+)
+
+// newTestQueue returns a directory whose queue lives in an in-memory redis, with no stored entries
+func newTestQueue(t *testing.T) *Directory {
+	t.Helper()
+
+	client := redistest.NewServer(t).Client(t)
+	return &Directory{
+		Logger:      zap.NewNop().Sugar(),
+		RedisClient: client,
+		RedisPrefix: "test",
+		Store:       &store.Store{Redis: client, RedisPrefix: "test"},
+		settings:    DefaultSettings,
+	}
+}
+
+func testDIDs(n int) []string {
+	dids := make([]string, n)
+	for i := range dids {
+		dids[i] = "did:plc:" + strings.Repeat(string(rune('a'+i)), 24)
+	}
+	return dids
+}
+
+func TestEnqueueRevalidation(t *testing.T) {
+	ctx := context.Background()
+	d := newTestQueue(t)
+	dids := testDIDs(2)
+
+	failed := map[string]*RevalidationResult{"nope.test": {Error: "handle not found"}}
+	id, err := d.EnqueueRevalidation(ctx, dids, failed, PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := d.GetRevalidationJob(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(job.Results) != 3 || job.Done() {
+		t.Fatalf("new job = %+v, want 3 identifiers with 2 pending", job.Results)
+	}
+	for _, did := range dids {
+		if result, ok := job.Results[did]; !ok || result != nil {
+			t.Errorf("result of %s = %+v, %t, want pending", did, result, ok)
+		}
+	}
+	if result := job.Results["nope.test"]; result == nil || result.Error != "handle not found" {
+		t.Errorf("result of nope.test = %+v, want the failure it was enqueued with", result)
+	}
+
+	// The job and its waiters expire on their own
+	if ttl := d.RedisClient.PTTL(ctx, d.revalidationJobKey(id)).Val(); ttl <= 0 || ttl > revalidationJobTTL {
+		t.Errorf("job TTL = %s, want up to %s", ttl, revalidationJobTTL)
+	}
+	for _, did := range dids {
+		waiters := d.RedisClient.SMembers(ctx, d.revalidationWaitersKey(did)).Val()
+		if len(waiters) != 1 || waiters[0] != id {
+			t.Errorf("waiters of %s = %v, want [%s]", did, waiters, id)
+		}
+	}
+	if n := d.RedisClient.ZCard(ctx, d.revalidationQueueKey()).Val(); n != 2 {
+		t.Errorf("queue length = %d, want 2", n)
+	}
+
+	if _, err := d.GetRevalidationJob(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("GetRevalidationJob of a missing job = %v, want ErrJobNotFound", err)
+	}
+	if _, err := d.EnqueueRevalidation(ctx, nil, nil, PriorityBatch); err == nil {
+		t.Error("EnqueueRevalidation accepted a job with no identifiers")
+	}
+}
+
+func TestQueuePriority(t *testing.T) {
+	ctx := context.Background()
+	d := newTestQueue(t)
+	dids := testDIDs(3)
+	stale, batch, single := dids[0], dids[1], dids[2]
+
+	if err := d.QueueRevalidation(ctx, dids, PriorityStale); err != nil {
+		t.Fatal(err)
+	}
+	staleScore := d.RedisClient.ZScore(ctx, d.revalidationQueueKey(), batch).Val()
+
+	// A higher priority moves an already queued DID up
+	if err := d.QueueRevalidation(ctx, []string{batch}, PriorityBatch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.EnqueueRevalidation(ctx, []string{single}, nil, PrioritySingle); err != nil {
+		t.Fatal(err)
+	}
+	batchScore := d.RedisClient.ZScore(ctx, d.revalidationQueueKey(), batch).Val()
+	if batchScore >= staleScore {
+		t.Errorf("score after raising the priority = %f, want below %f", batchScore, staleScore)
+	}
+
+	// A lower priority never moves it back down
+	if err := d.QueueRevalidation(ctx, []string{batch, single}, PriorityStale); err != nil {
+		t.Fatal(err)
+	}
+	if score := d.RedisClient.ZScore(ctx, d.revalidationQueueKey(), batch).Val(); score != batchScore {
+		t.Errorf("score after queuing at a lower priority = %f, want %f", score, batchScore)
+	}
+
+	order := d.RedisClient.ZRange(ctx, d.revalidationQueueKey(), 0, -1).Val()
+	want := []string{single, batch, stale}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("queue order = %v, want %v", order, want)
+	}
+}
+
+func TestProcessRevalidation(t *testing.T) {
+	ctx := context.Background()
+	d := newTestQueue(t)
+	did := testDIDs(1)[0]
+
+	// Two jobs waiting on the same DID are both answered by one check
+	first, err := d.EnqueueRevalidation(ctx, []string{did}, nil, PrioritySingle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := d.EnqueueRevalidation(ctx, []string{did}, nil, PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.processRevalidation(ctx, did)
+
+	for _, id := range []string{first, second} {
+		job, err := d.GetRevalidationJob(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := job.Results[did]
+		if !job.Done() || result == nil || result.Error != "DID not found" {
+			t.Errorf("job %s result = %+v, want DID not found", id, result)
+		}
+	}
+	if n := d.RedisClient.Exists(ctx, d.revalidationWaitersKey(did)).Val(); n != 0 {
+		t.Error("waiters were left behind after the DID was processed")
+	}
+}
+
+func TestRunRevalidationQueue(t *testing.T) {
+	ctx := context.Background()
+	d := newTestQueue(t)
+	d.settings.RevalidationConcurrency = 1
+	dids := testDIDs(3)
+
+	id, err := d.EnqueueRevalidation(ctx, dids, nil, PrioritySingle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.runRevalidationQueue(stop, ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := d.GetRevalidationJob(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Done() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job still pending: %+v", job.Results)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queue didn't stop")
+	}
+
+	if n := d.RedisClient.ZCard(ctx, d.revalidationQueueKey()).Val(); n != 0 {
+		t.Errorf("queue length after processing = %d, want 0", n)
+	}
+}
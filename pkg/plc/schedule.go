@@ -0,0 +1,105 @@
+package plc
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/store"
+)
+
+// Schedule decides when a handle is validated next based on how its recent validations went.
+// Valid handles back off gradually, invalid ones back off exponentially since most of them are dead domains,
+// and no check is scheduled before the DNS answer it was based on expires.
+type Schedule struct {
+	// ValidInterval is the delay after the first valid result, each further valid result adds ValidBackoff of it
+	ValidInterval    time.Duration
+	ValidBackoff     float64
+	MaxValidInterval time.Duration
+
+	// InvalidInterval is the delay after the first invalid result, it's multiplied by InvalidBackoff each time after
+	InvalidInterval    time.Duration
+	InvalidBackoff     float64
+	MaxInvalidInterval time.Duration
+
+	// RetryInterval is the delay after a transient failure that says nothing about the handle
+	RetryInterval time.Duration
+
+	// MinInterval is the shortest delay between checks regardless of DNS TTLs
+	MinInterval time.Duration
+	// Jitter spreads checks by up to this fraction of the interval so entries seen together don't stay in lockstep
+	Jitter float64
+}
+
+// DefaultSchedule rechecks valid handles every 12h at first, up to weekly, and invalid handles
+// after an hour at first, doubling up to monthly. Transient failures are retried after 15 minutes.
+var DefaultSchedule = Schedule{
+	ValidInterval:    12 * time.Hour,
+	ValidBackoff:     0.5,
+	MaxValidInterval: 7 * 24 * time.Hour,
+
+	InvalidInterval:    time.Hour,
+	InvalidBackoff:     2,
+	MaxInvalidInterval: 30 * 24 * time.Hour,
+
+	RetryInterval: 15 * time.Minute,
+
+	MinInterval: 5 * time.Minute,
+	Jitter:      0.1,
+}
+
+// Interval returns the delay before the next check after a validation with the given streak,
+// the number of validations in a row before this one with the same outcome
+func (s *Schedule) Interval(valid bool, streak int, dnsTTL time.Duration) time.Duration {
+	var interval float64
+	if valid {
+		interval = float64(s.ValidInterval) * (1 + s.ValidBackoff*float64(streak))
+		interval = math.Min(interval, float64(s.MaxValidInterval))
+	} else {
+		interval = float64(s.InvalidInterval) * math.Pow(s.InvalidBackoff, float64(streak))
+		interval = math.Min(interval, float64(s.MaxInvalidInterval))
+	}
+
+	next := s.jitter(interval)
+	if next < dnsTTL {
+		next = dnsTTL
+	}
+	if next < s.MinInterval {
+		next = s.MinInterval
+	}
+	return next
+}
+
+// Apply sets the entry's check streak and next check time from the previous state of the entry,
+// prev is nil if the entry has never been validated. The streak starts over when the handle changed.
+func (s *Schedule) Apply(entry *store.Entry, prev *store.Entry, dnsTTL time.Duration) {
+	streak := 0
+	if prev != nil && !prev.LastCheckedTime.IsZero() && prev.Handle == entry.Handle && prev.IsValid == entry.IsValid {
+		streak = prev.CheckStreak + 1
+	}
+
+	entry.CheckStreak = streak
+	entry.NextCheckTime = entry.LastCheckedTime.Add(s.Interval(entry.IsValid, streak, dnsTTL))
+}
+
+// Retry schedules the entry after a transient failure. The failure isn't another instance of the last outcome,
+// so the streak is carried over unchanged and the entry is checked again after RetryInterval.
+func (s *Schedule) Retry(entry *store.Entry, prev *store.Entry) {
+	streak := 0
+	if prev != nil && prev.Handle == entry.Handle {
+		streak = prev.CheckStreak
+	}
+
+	next := s.jitter(float64(s.RetryInterval))
+	if next < s.MinInterval {
+		next = s.MinInterval
+	}
+
+	entry.CheckStreak = streak
+	entry.NextCheckTime = entry.LastCheckedTime.Add(next)
+}
+
+// jitter spreads an interval by up to Jitter of it either way
+func (s *Schedule) jitter(interval float64) time.Duration {
+	return time.Duration(interval + interval*s.Jitter*(2*rand.Float64()-1))
+}
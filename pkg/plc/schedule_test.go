@@ -0,0 +1,112 @@
+package plc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/store"
+)
+
+// testSchedule is the default schedule without jitter so intervals are exact
+func testSchedule() *Schedule {
+	s := DefaultSchedule
+	s.Jitter = 0
+	return &s
+}
+
+func TestScheduleInterval(t *testing.T) {
+	s := testSchedule()
+
+	tests := []struct {
+		valid  bool
+		streak int
+		dnsTTL time.Duration
+		want   time.Duration
+	}{
+		{true, 0, 0, 12 * time.Hour},
+		{true, 2, 0, 24 * time.Hour},
+		{true, 100, 0, 7 * 24 * time.Hour},
+		{false, 0, 0, time.Hour},
+		{false, 3, 0, 8 * time.Hour},
+		{false, 100, 0, 30 * 24 * time.Hour},
+		// Never before the DNS answer expires, and never sooner than MinInterval
+		{false, 0, 3 * time.Hour, 3 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := s.Interval(tt.valid, tt.streak, tt.dnsTTL); got != tt.want {
+			t.Errorf("Interval(%t, %d, %s) = %s, want %s", tt.valid, tt.streak, tt.dnsTTL, got, tt.want)
+		}
+	}
+
+	s.InvalidInterval = time.Minute
+	if got := s.Interval(false, 0, 0); got != s.MinInterval {
+		t.Errorf("Interval below MinInterval = %s, want %s", got, s.MinInterval)
+	}
+}
+
+func TestScheduleApplyStreak(t *testing.T) {
+	s := testSchedule()
+	now := time.Now()
+	prev := &store.Entry{Handle: "alice.test", IsValid: true, LastCheckedTime: now.Add(-time.Hour), CheckStreak: 2}
+
+	entry := &store.Entry{Handle: "alice.test", IsValid: true, LastCheckedTime: now}
+	s.Apply(entry, prev, 0)
+	if entry.CheckStreak != 3 || !entry.NextCheckTime.Equal(now.Add(s.Interval(true, 3, 0))) {
+		t.Errorf("repeated outcome: streak %d, next check %s", entry.CheckStreak, entry.NextCheckTime.Sub(now))
+	}
+
+	// A different outcome, a different handle or no previous check start over
+	for name, entry := range map[string]*store.Entry{
+		"flipped": {Handle: "alice.test", IsValid: false, LastCheckedTime: now},
+		"renamed": {Handle: "bob.test", IsValid: true, LastCheckedTime: now},
+	} {
+		s.Apply(entry, prev, 0)
+		if entry.CheckStreak != 0 {
+			t.Errorf("%s: streak %d, want 0", name, entry.CheckStreak)
+		}
+	}
+	entry = &store.Entry{Handle: "alice.test", IsValid: true, LastCheckedTime: now}
+	s.Apply(entry, nil, 0)
+	if entry.CheckStreak != 0 {
+		t.Errorf("first check: streak %d, want 0", entry.CheckStreak)
+	}
+}
+
+func TestValidationEntryTransient(t *testing.T) {
+	s := testSchedule()
+	prev := &store.Entry{
+		Handle:          "alice.test",
+		IsValid:         true,
+		LastCheckedTime: time.Now().Add(-12 * time.Hour),
+		CheckStreak:     4,
+	}
+
+	// A DID document that couldn't be loaded says nothing about the handle
+	v := newValidation(store.ValidationStatusDocumentError, errors.New("failed to load DID document"))
+	entry := v.Entry(s, prev, testDID, "alice.test")
+
+	if !entry.IsValid {
+		t.Error("a transient failure changed the last outcome")
+	}
+	if entry.CheckStreak != prev.CheckStreak {
+		t.Errorf("streak = %d, want it kept at %d", entry.CheckStreak, prev.CheckStreak)
+	}
+	if next := entry.NextCheckTime.Sub(entry.LastCheckedTime); next != s.RetryInterval {
+		t.Errorf("next check in %s, want the retry interval %s", next, s.RetryInterval)
+	}
+
+	// For a handle that changed there's no outcome to keep
+	entry = v.Entry(s, prev, testDID, "bob.test")
+	if entry.IsValid || entry.CheckStreak != 0 {
+		t.Errorf("transient failure of a new handle: valid %t, streak %d", entry.IsValid, entry.CheckStreak)
+	}
+
+	// Other failures are outcomes of their own
+	v = newValidation(store.ValidationStatusNXDomain, errors.New("no such host"))
+	entry = v.Entry(s, prev, testDID, "alice.test")
+	if entry.IsValid || entry.CheckStreak != 0 || entry.NextCheckTime.Sub(entry.LastCheckedTime) != s.InvalidInterval {
+		t.Errorf("invalid result: valid %t, streak %d, next check in %s",
+			entry.IsValid, entry.CheckStreak, entry.NextCheckTime.Sub(entry.LastCheckedTime))
+	}
+}
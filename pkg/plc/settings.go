@@ -0,0 +1,49 @@
+package plc
+
+import "time"
+
+// Settings are the directory's tuning knobs, they can be changed with Configure while it's running
+type Settings struct {
+	// CheckPeriod is how often the PLC directory is polled for new operations
+	CheckPeriod time.Duration
+
+	// ValidationPageSize is the number of due entries validated per page
+	ValidationPageSize int
+	// ValidationConcurrency is the number of handles validated at once within a page
+	ValidationConcurrency int
+	// ValidationLease is how long a page's entries are reserved for this replica, the lease is renewed
+	// every third of it while the page runs so it only lapses once the replica stops renewing it
+	ValidationLease time.Duration
+	// RevalidationConcurrency is the number of on-demand revalidations run at once, they run alongside
+	// the scheduled pages rather than waiting behind them, so they add to ValidationConcurrency
+	RevalidationConcurrency int
+
+	// Schedule decides when each handle is validated next
+	Schedule Schedule
+}
+
+var DefaultSettings = Settings{
+	CheckPeriod: 30 * time.Second,
+
+	ValidationPageSize:    1200,
+	ValidationConcurrency: 20,
+	ValidationLease:       10 * time.Minute,
+
+	RevalidationConcurrency: 10,
+
+	Schedule: DefaultSchedule,
+}
+
+// Settings returns the directory's current settings
+func (d *Directory) Settings() Settings {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	return d.settings
+}
+
+// Configure replaces the directory's settings, running loops pick them up on their next iteration
+func (d *Directory) Configure(settings Settings) {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	d.settings = settings
+}
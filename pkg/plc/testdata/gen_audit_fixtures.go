@@ -0,0 +1,329 @@
+//go:build ignore
+
+// gen_audit_fixtures signs the PLC operations in audit_operations.json with fixed test keys.
+// It encodes DAG-CBOR by hand rather than through the package's encoder, so the fixtures check
+// the package against an independent encoding. Regenerate with:
+//
+//	go run ./pkg/plc/testdata/gen_audit_fixtures.go > pkg/plc/testdata/audit_operations.json
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/mr-tron/base58"
+)
+
+// kv is a map entry, maps are kept as ordered entries so non-canonical encodings can be produced too
+type kv struct {
+	Key   string
+	Value any
+}
+
+type obj []kv
+
+func (o obj) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, e := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(e.Key)
+		v, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o obj) with(key string, value any) obj {
+	out := obj{}
+	replaced := false
+	for _, e := range o {
+		if e.Key == key {
+			out = append(out, kv{key, value})
+			replaced = true
+			continue
+		}
+		out = append(out, e)
+	}
+	if !replaced {
+		out = append(out, kv{key, value})
+	}
+	return out
+}
+
+func (o obj) without(key string) obj {
+	out := obj{}
+	for _, e := range o {
+		if e.Key != key {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func cborHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major<<5 | byte(n))
+	case n < 1<<8:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(n))
+	case n < 1<<16:
+		buf.WriteByte(major<<5 | 25)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(major<<5 | 26)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+// encode writes DAG-CBOR, with map keys sorted by length and then bytewise when canonical is set
+func encode(buf *bytes.Buffer, v any, canonical bool) {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteByte(0xf6)
+	case string:
+		cborHead(buf, 3, uint64(len(val)))
+		buf.WriteString(val)
+	case []any:
+		cborHead(buf, 4, uint64(len(val)))
+		for _, item := range val {
+			encode(buf, item, canonical)
+		}
+	case obj:
+		entries := append(obj{}, val...)
+		if canonical {
+			sort.Slice(entries, func(i, j int) bool {
+				a, b := entries[i].Key, entries[j].Key
+				if len(a) != len(b) {
+					return len(a) < len(b)
+				}
+				return a < b
+			})
+		}
+		cborHead(buf, 5, uint64(len(entries)))
+		for _, e := range entries {
+			encode(buf, e.Key, canonical)
+			encode(buf, e.Value, canonical)
+		}
+	default:
+		panic("unsupported value")
+	}
+}
+
+func dagCBOR(v any, canonical bool) []byte {
+	var buf bytes.Buffer
+	encode(&buf, v, canonical)
+	return buf.Bytes()
+}
+
+var base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+func cid(op obj) string {
+	hash := sha256.Sum256(dagCBOR(op, true))
+	return "b" + base32Lower.EncodeToString(append([]byte{0x01, 0x71, 0x12, 0x20}, hash[:]...))
+}
+
+func did(genesis obj) string {
+	hash := sha256.Sum256(dagCBOR(genesis, true))
+	return "did:plc:" + base32Lower.EncodeToString(hash[:])[:24]
+}
+
+func seed(label string) []byte {
+	hash := sha256.Sum256([]byte("bingo audit fixture " + label))
+	return hash[:]
+}
+
+// signer produces compact 64 byte r || s signatures
+type signer struct {
+	didKey string
+	sign   func(hash []byte) []byte
+	n      *big.Int
+}
+
+func secp256k1Signer() signer {
+	priv := secp256k1.PrivKeyFromBytes(seed("secp256k1"))
+	return signer{
+		didKey: "did:key:z" + base58.Encode(append([]byte{0xe7, 0x01}, priv.PubKey().SerializeCompressed()...)),
+		sign: func(hash []byte) []byte {
+			// Compact signatures are always low-S, drop the recovery byte
+			return secp256k1ecdsa.SignCompact(priv, hash, true)[1:]
+		},
+		n: secp256k1.S256().N,
+	}
+}
+
+// deterministicReader makes P-256 signatures reproducible
+type deterministicReader struct{ state [32]byte }
+
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	for i := range p {
+		if i%32 == 0 {
+			r.state = sha256.Sum256(r.state[:])
+		}
+		p[i] = r.state[i%32]
+	}
+	return len(p), nil
+}
+
+func p256Signer() signer {
+	curve := elliptic.P256()
+	d := new(big.Int).SetBytes(seed("p256"))
+	d.Mod(d, curve.Params().N)
+	priv := &ecdsa.PrivateKey{D: d}
+	priv.PublicKey.Curve = curve
+	priv.PublicKey.X, priv.PublicKey.Y = curve.ScalarBaseMult(d.Bytes())
+
+	rng := &deterministicReader{state: sha256.Sum256(seed("p256 nonce"))}
+	n := curve.Params().N
+	return signer{
+		didKey: "did:key:z" + base58.Encode(append([]byte{0x80, 0x24}, elliptic.MarshalCompressed(curve, priv.X, priv.Y)...)),
+		sign: func(hash []byte) []byte {
+			r, s, err := ecdsa.Sign(rng, priv, hash)
+			if err != nil {
+				panic(err)
+			}
+			// PLC only accepts low-S signatures
+			if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+				s.Sub(n, s)
+			}
+			sig := make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+			return sig
+		},
+		n: n,
+	}
+}
+
+// signed signs the canonical encoding of an unsigned operation
+func (s signer) signed(unsigned obj) obj {
+	hash := sha256.Sum256(dagCBOR(unsigned, true))
+	return unsigned.with("sig", base64.RawURLEncoding.EncodeToString(s.sign(hash[:])))
+}
+
+// highS flips a signed operation's signature to its high-S twin, which is just as valid to plain ECDSA
+func (s signer) highS(op obj) obj {
+	for _, e := range op {
+		if e.Key != "sig" {
+			continue
+		}
+		sig, _ := base64.RawURLEncoding.DecodeString(e.Value.(string))
+		sVal := new(big.Int).SetBytes(sig[32:])
+		sVal.Sub(s.n, sVal)
+		sVal.FillBytes(sig[32:])
+		return op.with("sig", base64.RawURLEncoding.EncodeToString(sig))
+	}
+	panic("operation isn't signed")
+}
+
+type row struct {
+	Did       string `json:"did"`
+	Operation obj    `json:"operation"`
+	Cid       string `json:"cid"`
+	Nullified bool   `json:"nullified"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type fixtureCase struct {
+	Name string `json:"name"`
+	Row  row    `json:"row"`
+	// Err is a substring of the expected verification error, empty if the operation verifies
+	Err string `json:"err"`
+}
+
+type fixtures struct {
+	Keys  map[string]string `json:"keys"`
+	Log   []row             `json:"log"`
+	Cases []fixtureCase     `json:"cases"`
+}
+
+func main() {
+	k1 := secp256k1Signer()
+	p256 := p256Signer()
+
+	genesis := k1.signed(obj{
+		{"type", "plc_operation"},
+		{"rotationKeys", []any{k1.didKey, p256.didKey}},
+		{"verificationMethods", obj{{"atproto", k1.didKey}}},
+		{"alsoKnownAs", []any{"at://alice.test"}},
+		{"services", obj{{"atproto_pds", obj{{"type", "AtprotoPersonalDataServer"}, {"endpoint", "https://pds.test"}}}}},
+		{"prev", nil},
+	})
+	id := did(genesis)
+	genesisRow := row{Did: id, Operation: genesis, Cid: cid(genesis), CreatedAt: "2023-06-01T00:00:00.000Z"}
+
+	unsignedUpdate := genesis.without("sig").
+		with("alsoKnownAs", []any{"at://alice2.test"}).
+		with("prev", genesisRow.Cid)
+	update := p256.signed(unsignedUpdate)
+	updateRow := row{Did: id, Operation: update, Cid: cid(update), CreatedAt: "2023-06-02T00:00:00.000Z"}
+
+	secondUpdate := k1.signed(update.without("sig").
+		with("alsoKnownAs", []any{"at://alice3.test"}).
+		with("prev", updateRow.Cid))
+
+	at := func(op obj, createdAt string) row {
+		return row{Did: id, Operation: op, Cid: cid(op), CreatedAt: createdAt}
+	}
+	later := "2023-06-03T00:00:00.000Z"
+
+	// Signed over the JSON field order instead of canonical DAG-CBOR
+	nonCanonicalHash := sha256.Sum256(dagCBOR(unsignedUpdate, false))
+	nonCanonical := unsignedUpdate.with("sig", base64.RawURLEncoding.EncodeToString(p256.sign(nonCanonicalHash[:])))
+
+	// Signed with the sig field still present, as an empty string
+	withSigHash := sha256.Sum256(dagCBOR(unsignedUpdate.with("sig", ""), true))
+	withSig := unsignedUpdate.with("sig", base64.RawURLEncoding.EncodeToString(p256.sign(withSigHash[:])))
+
+	wrongCid := updateRow
+	wrongCid.Cid = genesisRow.Cid
+
+	wrongDid := genesisRow
+	wrongDid.Did = "did:plc:aaaaaaaaaaaaaaaaaaaaaaaa"
+
+	unknownPrev := k1.signed(unsignedUpdate.with("prev", cid(secondUpdate)))
+
+	out := fixtures{
+		Keys: map[string]string{"secp256k1": k1.didKey, "p256": p256.didKey},
+		Log:  []row{genesisRow, updateRow},
+		Cases: []fixtureCase{
+			{Name: "secp256k1 genesis", Row: genesisRow},
+			{Name: "p256 update", Row: updateRow},
+			{Name: "secp256k1 update", Row: at(secondUpdate, later)},
+			{Name: "secp256k1 high-S", Row: at(k1.highS(secondUpdate), later), Err: "signature does not match"},
+			{Name: "p256 high-S", Row: at(p256.highS(update), later), Err: "signature does not match"},
+			{Name: "tampered after signing", Row: at(update.with("alsoKnownAs", []any{"at://mallory.test"}), later), Err: "signature does not match"},
+			{Name: "signed non-canonical encoding", Row: at(nonCanonical, later), Err: "signature does not match"},
+			{Name: "signed with sig field", Row: at(withSig, later), Err: "signature does not match"},
+			{Name: "cid mismatch", Row: wrongCid, Err: "CID mismatch"},
+			{Name: "genesis derives another did", Row: wrongDid, Err: "genesis operation derives"},
+			{Name: "unknown prev", Row: at(unknownPrev, later), Err: "cannot be verified locally"},
+		},
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,221 @@
+package plc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+)
+
+var plcUpstreamHealthGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "plc_directory_upstream_health_score",
+	Help: "Health score (0-1) of each PLC directory upstream, based on recent request outcomes",
+}, []string{"endpoint"})
+
+var ErrNoHealthyUpstream = errors.New("all PLC upstreams failed")
+
+const (
+	// healthDecay weights how much each request outcome moves an upstream's health score
+	healthDecay = 0.3
+	// unhealthyScore is the score below which an upstream is only tried after healthy ones
+	unhealthyScore = 0.5
+	// maxConsecutiveFailures before an upstream is put in cooldown
+	maxConsecutiveFailures = 3
+	minCooldown            = 30 * time.Second
+	maxCooldown            = 5 * time.Minute
+)
+
+// Upstream is a single PLC directory (the primary or a mirror) with its own rate limit and health score
+type Upstream struct {
+	// Endpoint is the export URL of the upstream, e.g. https://plc.directory/export
+	Endpoint    string
+	RateLimiter *rate.Limiter
+
+	lk                  sync.Mutex
+	score               float64
+	consecutiveFailures int
+	cooldown            time.Duration
+	cooldownUntil       time.Time
+}
+
+// BaseURL returns the root of the PLC directory the upstream's export endpoint belongs to
+func (u *Upstream) BaseURL() string {
+	return strings.TrimSuffix(u.Endpoint, "/export")
+}
+
+// Observe records the outcome of a request to the upstream
+func (u *Upstream) Observe(success bool) {
+	u.lk.Lock()
+	defer u.lk.Unlock()
+
+	outcome := 0.0
+	if success {
+		outcome = 1.0
+	}
+	u.score = (1-healthDecay)*u.score + healthDecay*outcome
+	plcUpstreamHealthGauge.WithLabelValues(u.Endpoint).Set(u.score)
+
+	if success {
+		u.consecutiveFailures = 0
+		u.cooldown = 0
+		return
+	}
+
+	u.consecutiveFailures++
+	if u.consecutiveFailures >= maxConsecutiveFailures {
+		// Back off exponentially while the upstream keeps failing once its cooldown expires
+		if u.cooldown == 0 {
+			u.cooldown = minCooldown
+		} else if u.cooldown < maxCooldown {
+			u.cooldown *= 2
+		}
+		if u.cooldown > maxCooldown {
+			u.cooldown = maxCooldown
+		}
+		u.cooldownUntil = time.Now().Add(u.cooldown)
+	}
+}
+
+func (u *Upstream) health() (float64, bool) {
+	u.lk.Lock()
+	defer u.lk.Unlock()
+	return u.score, time.Now().Before(u.cooldownUntil)
+}
+
+// UpstreamPool fails over between PLC upstreams in order of health, preferring the configured order
+type UpstreamPool struct {
+	Upstreams []*Upstream
+}
+
+func NewUpstreamPool(endpoints []string, requestsPerSecond float64) (*UpstreamPool, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one PLC endpoint is required")
+	}
+
+	pool := &UpstreamPool{}
+	for _, endpoint := range endpoints {
+		pool.Upstreams = append(pool.Upstreams, &Upstream{
+			Endpoint:    endpoint,
+			RateLimiter: rate.NewLimiter(rate.Limit(requestsPerSecond), 1),
+			score:       1,
+		})
+		plcUpstreamHealthGauge.WithLabelValues(endpoint).Set(1)
+	}
+
+	return pool, nil
+}
+
+// Candidates returns the upstreams in the order they should be tried:
+// healthy upstreams first, then degraded ones, then ones cooling down as a last resort
+func (p *UpstreamPool) Candidates() []*Upstream {
+	type candidate struct {
+		upstream *Upstream
+		tier     int
+	}
+
+	candidates := make([]candidate, 0, len(p.Upstreams))
+	for _, u := range p.Upstreams {
+		score, coolingDown := u.health()
+		tier := 0
+		switch {
+		case coolingDown:
+			tier = 2
+		case score < unhealthyScore:
+			tier = 1
+		}
+		candidates = append(candidates, candidate{upstream: u, tier: tier})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].tier < candidates[j].tier
+	})
+
+	upstreams := make([]*Upstream, 0, len(candidates))
+	for _, c := range candidates {
+		upstreams = append(upstreams, c.upstream)
+	}
+	return upstreams
+}
+
+// CheckHealth probes every upstream with a minimal export request so degraded upstreams
+// that aren't picked for regular traffic can recover their health score
+func (p *UpstreamPool) CheckHealth(ctx context.Context, client *http.Client) {
+	for _, u := range p.Upstreams {
+		req, err := http.NewRequestWithContext(ctx, "GET", u.Endpoint+"?count=1", nil)
+		if err != nil {
+			continue
+		}
+
+		if err := u.RateLimiter.Wait(ctx); err != nil {
+			return
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			plcDirectoryRequestHistogram.WithLabelValues(u.Endpoint, "error").Observe(time.Since(start).Seconds())
+			u.Observe(false)
+			continue
+		}
+		resp.Body.Close()
+		plcDirectoryRequestHistogram.WithLabelValues(u.Endpoint, fmt.Sprintf("%d", resp.StatusCode)).Observe(time.Since(start).Seconds())
+
+		u.Observe(resp.StatusCode == http.StatusOK)
+	}
+}
+
+// SetLimit updates the rate limit of every upstream
+func (p *UpstreamPool) SetLimit(requestsPerSecond float64) {
+	for _, u := range p.Upstreams {
+		u.RateLimiter.SetLimit(rate.Limit(requestsPerSecond))
+	}
+}
+
+// Do sends a GET request to the healthiest upstream, failing over to the next one on errors,
+// 429s and 5xxs. The caller is responsible for closing the response body.
+func (p *UpstreamPool) Do(ctx context.Context, client *http.Client, buildURL func(u *Upstream) string) (*http.Response, *Upstream, error) {
+	var errs []error
+	for _, u := range p.Candidates() {
+		req, err := http.NewRequestWithContext(ctx, "GET", buildURL(u), nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if err := u.RateLimiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			plcDirectoryRequestHistogram.WithLabelValues(u.Endpoint, "error").Observe(time.Since(start).Seconds())
+			u.Observe(false)
+			errs = append(errs, fmt.Errorf("%s: %w", u.Endpoint, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		plcDirectoryRequestHistogram.WithLabelValues(u.Endpoint, fmt.Sprintf("%d", resp.StatusCode)).Observe(time.Since(start).Seconds())
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			resp.Body.Close()
+			u.Observe(false)
+			errs = append(errs, fmt.Errorf("%s: %s", u.Endpoint, resp.Status))
+			continue
+		}
+
+		u.Observe(true)
+		return resp, u, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: %w", ErrNoHealthyUpstream, errors.Join(errs...))
+}
@@ -0,0 +1,148 @@
+package plc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/fetcher"
+	"github.com/ericvolp12/bingo/pkg/resolver"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var validationStatusCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "handle_validation_status_total",
+	Help: "Total number of handle validations by outcome",
+}, []string{"status"})
+
+// maxValidationDetail caps the stored explanation of a validation failure
+const maxValidationDetail = 512
+
+// Validation is the outcome of validating a handle, with the errors that explain a failure
+type Validation struct {
+	Status store.ValidationStatus
+	Errors []error
+
+	// DNSTTL is how long the TXT answer the validation used stays cached, zero if unknown
+	DNSTTL time.Duration
+}
+
+func newValidation(status store.ValidationStatus, errs ...error) *Validation {
+	return &Validation{Status: status, Errors: errs}
+}
+
+func (v *Validation) Valid() bool {
+	return v.Status == store.ValidationStatusValid
+}
+
+// Transient reports whether the validation failed for reasons on our side that say nothing about the handle
+func (v *Validation) Transient() bool {
+	return v.Status == store.ValidationStatusDocumentError
+}
+
+// Detail summarizes the errors behind a failed validation in a short string fit for storing
+func (v *Validation) Detail() string {
+	msgs := make([]string, 0, len(v.Errors))
+	for _, err := range v.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	detail := strings.Join(msgs, "; ")
+	if len(detail) > maxValidationDetail {
+		detail = detail[:maxValidationDetail]
+	}
+
+	// Details end up in postgres, which rejects NUL bytes and invalid UTF-8 (like a rune cut in half above)
+	return strings.ToValidUTF8(strings.ReplaceAll(detail, "\x00", ""), "")
+}
+
+// and combines the failures of the TXT and HTTPS checks.
+// TXT records that exist but point elsewhere are the clearest explanation, otherwise the HTTPS failure is reported.
+func (v *Validation) and(https *Validation) *Validation {
+	status := https.Status
+	if v.Status == store.ValidationStatusTXTMismatch {
+		status = v.Status
+	}
+
+	return &Validation{
+		Status: status,
+		Errors: append(append([]error{}, v.Errors...), https.Errors...),
+	}
+}
+
+// Entry returns the store entry recording this validation of a handle, scheduled relative to prev,
+// the entry as it was before the validation or nil if there wasn't one
+func (v *Validation) Entry(schedule *Schedule, prev *store.Entry, did string, handle string) *store.Entry {
+	validationStatusCounter.WithLabelValues(string(v.Status)).Inc()
+
+	entry := &store.Entry{
+		Did:              did,
+		Handle:           handle,
+		IsValid:          v.Valid(),
+		LastCheckedTime:  time.Now(),
+		ValidationStatus: v.Status,
+		ValidationDetail: v.Detail(),
+	}
+
+	// A transient failure keeps whatever the last real outcome for the same handle was, and is retried soon
+	if v.Transient() {
+		if prev != nil && prev.Handle == handle {
+			entry.IsValid = prev.IsValid
+		}
+		schedule.Retry(entry, prev)
+		return entry
+	}
+	schedule.Apply(entry, prev, v.DNSTTL)
+
+	return entry
+}
+
+// dnsErrorStatus classifies a failed TXT lookup, only a name that doesn't exist is nxdomain
+func dnsErrorStatus(err error) store.ValidationStatus {
+	if resolver.IsNXDomain(err) {
+		return store.ValidationStatusNXDomain
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return store.ValidationStatusNoRecord
+	}
+	return store.ValidationStatusDNSError
+}
+
+// httpErrorStatus classifies a failed request for /.well-known/atproto-did.
+// The host is resolved by net.Resolver, which can't tell a missing name from one without addresses, so both are nxdomain.
+func httpErrorStatus(err error) store.ValidationStatus {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return store.ValidationStatusNXDomain
+	}
+
+	if errors.Is(err, fetcher.ErrBodyTooLarge) {
+		return store.ValidationStatusBodyTooLarge
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return store.ValidationStatusTimeout
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var hostnameErr x509.HostnameError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &certErr) || errors.As(err, &recordErr) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &invalidErr) ||
+		// Handshake alerts aren't exported as types, only their messages identify them
+		strings.Contains(err.Error(), "tls: ") {
+		return store.ValidationStatusTLSError
+	}
+
+	return store.ValidationStatusConnectionError
+}
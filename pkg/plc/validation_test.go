@@ -0,0 +1,127 @@
+package plc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ericvolp12/bingo/pkg/fetcher"
+	"github.com/ericvolp12/bingo/pkg/resolver"
+	"github.com/ericvolp12/bingo/pkg/store"
+	"golang.org/x/time/rate"
+)
+
+const testDID = "did:plc:ewvi7nxzyoun6zhxrhs64oiz"
+
+// newTestValidator returns a directory that resolves TXT records through fake
+// and serves every handle's /.well-known/atproto-did from wellKnown
+func newTestValidator(t *testing.T, fake *resolver.Fake, wellKnown http.HandlerFunc) *Directory {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(wellKnown)
+	t.Cleanup(srv.Close)
+
+	// Route every handle to the test server, which presents a certificate for example.com
+	transport := srv.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	transport.TLSClientConfig.ServerName = "example.com"
+
+	return &Directory{
+		Resolver:      resolver.NewCache(fake, 100),
+		DomainLimiter: NewDomainLimiter(rate.Inf, 1, nil),
+		Fetcher: &fetcher.Fetcher{
+			Client:      &http.Client{Transport: transport},
+			MaxBodySize: fetcher.DefaultMaxBodySize,
+		},
+	}
+}
+
+func TestDNSErrorStatus(t *testing.T) {
+	fake := resolver.NewFake()
+	fake.SetTXT("_atproto.empty.test")
+	fake.SetError("_atproto.flaky.test", &net.DNSError{Err: "server misbehaving", IsTemporary: true})
+
+	tests := []struct {
+		name   string
+		status store.ValidationStatus
+	}{
+		{"_atproto.missing.test", store.ValidationStatusNXDomain},
+		{"_atproto.empty.test", store.ValidationStatusNoRecord},
+		{"_atproto.flaky.test", store.ValidationStatusDNSError},
+	}
+
+	for _, tt := range tests {
+		_, err := fake.LookupTXT(context.Background(), tt.name)
+		if got := dnsErrorStatus(err); got != tt.status {
+			t.Errorf("dnsErrorStatus(%v) = %s, want %s", err, got, tt.status)
+		}
+	}
+
+	// net.Resolver reports missing names and names without records alike
+	if got := dnsErrorStatus(&net.DNSError{Err: "no such host", IsNotFound: true}); got != store.ValidationStatusNXDomain {
+		t.Errorf("dnsErrorStatus of net.Resolver's not found = %s, want nxdomain", got)
+	}
+}
+
+func TestValidateHandleRecords(t *testing.T) {
+	fake := resolver.NewFake()
+	fake.SetTXT("_atproto.txt.test", "did="+testDID)
+	fake.SetTXT("_atproto.elsewhere.test", "did=did:plc:aaaaaaaaaaaaaaaaaaaaaaaa")
+	fake.SetTXT("_atproto.elsewhere-https.test", "did=did:plc:aaaaaaaaaaaaaaaaaaaaaaaa")
+	fake.SetError("_atproto.flaky.test", &net.DNSError{Err: "server misbehaving", IsTemporary: true})
+
+	d := newTestValidator(t, fake, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/atproto-did" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Host {
+		case "https.test", "flaky.test", "elsewhere-https.test":
+			w.Write([]byte(testDID + "\n"))
+		case "wrong-body.test":
+			w.Write([]byte("did:plc:aaaaaaaaaaaaaaaaaaaaaaaa\n"))
+		case "huge-body.test":
+			w.Write([]byte(strings.Repeat("x", fetcher.DefaultMaxBodySize+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	tests := []struct {
+		handle string
+		status store.ValidationStatus
+	}{
+		{"txt.test", store.ValidationStatusValid},
+		{"https.test", store.ValidationStatusValid},
+		// A TXT record pointing elsewhere doesn't stop the HTTPS check
+		{"elsewhere-https.test", store.ValidationStatusValid},
+		{"flaky.test", store.ValidationStatusValid},
+		// When both fail, TXT records pointing elsewhere explain it best
+		{"elsewhere.test", store.ValidationStatusTXTMismatch},
+		{"missing.test", store.ValidationStatusHTTPStatus},
+		{"wrong-body.test", store.ValidationStatusBodyMismatch},
+		{"huge-body.test", store.ValidationStatusBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.handle, func(t *testing.T) {
+			validation := d.validateHandleRecords(context.Background(), testDID, tt.handle)
+			if validation.Status != tt.status {
+				t.Fatalf("status = %s, want %s (errors: %v)", validation.Status, tt.status, validation.Errors)
+			}
+			if validation.Valid() != (tt.status == store.ValidationStatusValid) {
+				t.Errorf("Valid() = %t", validation.Valid())
+			}
+		})
+	}
+
+	// Answers served from the DNS cache report how long they stay cached
+	if validation := d.validateHandleRecords(context.Background(), testDID, "txt.test"); validation.DNSTTL <= 0 {
+		t.Errorf("DNSTTL = %s, want the cached answer's remaining TTL", validation.DNSTTL)
+	}
+}
@@ -0,0 +1,651 @@
+// Package redistest runs a small in-memory Redis server for tests.
+// It speaks RESP2 and implements only the commands Bingo uses.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Server is an in-memory Redis server listening on a local port
+type Server struct {
+	ln net.Listener
+
+	lk      sync.Mutex
+	strings map[string]string
+	hashes  map[string]map[string]string
+	sets    map[string]map[string]bool
+	zsets   map[string]map[string]float64
+	expires map[string]time.Time
+	closed  bool
+}
+
+// NewServer starts a server that's closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		ln:      ln,
+		strings: map[string]string{},
+		hashes:  map[string]map[string]string{},
+		sets:    map[string]map[string]bool{},
+		zsets:   map[string]map[string]float64{},
+		expires: map[string]time.Time{},
+	}
+	t.Cleanup(s.Close)
+
+	go s.serve()
+	return s
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Client returns a client for the server that's closed when the test ends
+func (s *Server) Client(t testing.TB) *redis.Client {
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.lk.Lock()
+	s.closed = true
+	s.lk.Unlock()
+	s.ln.Close()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+
+	var queued [][]string
+	inMulti := false
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		name := strings.ToUpper(args[0])
+
+		switch {
+		case name == "MULTI":
+			inMulti = true
+			queued = nil
+			w.WriteString("+OK\r\n")
+		case name == "EXEC" && inMulti:
+			inMulti = false
+			// Commands in a transaction run without anything in between
+			s.lk.Lock()
+			replies := make([]reply, len(queued))
+			for i, cmd := range queued {
+				replies[i] = s.exec(cmd)
+			}
+			s.lk.Unlock()
+			fmt.Fprintf(w, "*%d\r\n", len(replies))
+			for _, rep := range replies {
+				rep.write(w)
+			}
+		case inMulti:
+			queued = append(queued, args)
+			w.WriteString("+QUEUED\r\n")
+		case name == "BZPOPMIN":
+			s.bzpopmin(args).write(w)
+		default:
+			s.lk.Lock()
+			rep := s.exec(args)
+			s.lk.Unlock()
+			rep.write(w)
+		}
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("bad array header %q", line)
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimRight(header, "\r\n")[1:])
+		if err != nil {
+			return nil, fmt.Errorf("bad bulk header %q", header)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// reply is a RESP2 value: nil, string (bulk), status, int64, error or []reply
+type reply struct{ v any }
+
+type status string
+
+func (rep reply) write(w *bufio.Writer) {
+	switch v := rep.v.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case status:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case error:
+		fmt.Fprintf(w, "-%s\r\n", v.Error())
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []reply:
+		if v == nil {
+			w.WriteString("*-1\r\n")
+			return
+		}
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			item.write(w)
+		}
+	}
+}
+
+var (
+	ok          = reply{status("OK")}
+	errSyntax   = reply{errors.New("ERR syntax error")}
+	errArgs     = reply{errors.New("ERR wrong number of arguments")}
+	errWrongTyp = reply{errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")}
+)
+
+func strs(values []string) reply {
+	replies := make([]reply, len(values))
+	for i, v := range values {
+		replies[i] = reply{v}
+	}
+	return reply{replies}
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+// expire drops a key whose TTL has passed, the lock must be held
+func (s *Server) expire(key string) {
+	if at, ok := s.expires[key]; ok && !time.Now().Before(at) {
+		s.del(key)
+	}
+}
+
+func (s *Server) del(key string) bool {
+	_, a := s.strings[key]
+	_, b := s.hashes[key]
+	_, c := s.sets[key]
+	_, d := s.zsets[key]
+	delete(s.strings, key)
+	delete(s.hashes, key)
+	delete(s.sets, key)
+	delete(s.zsets, key)
+	delete(s.expires, key)
+	return a || b || c || d
+}
+
+func (s *Server) exists(key string) bool {
+	s.expire(key)
+	_, a := s.strings[key]
+	_, b := s.hashes[key]
+	_, c := s.sets[key]
+	_, d := s.zsets[key]
+	return a || b || c || d
+}
+
+// exec runs a command, the lock must be held
+func (s *Server) exec(args []string) reply {
+	name := strings.ToUpper(args[0])
+	args = args[1:]
+	for _, key := range keysOf(name, args) {
+		s.expire(key)
+	}
+
+	switch name {
+	case "PING":
+		return reply{status("PONG")}
+	case "SELECT":
+		return ok
+	case "GET":
+		if len(args) != 1 {
+			return errArgs
+		}
+		if v, found := s.strings[args[0]]; found {
+			return reply{v}
+		}
+		if s.exists(args[0]) {
+			return errWrongTyp
+		}
+		return reply{nil}
+	case "SET":
+		return s.set(args)
+	case "DEL":
+		var n int64
+		for _, key := range args {
+			if s.del(key) {
+				n++
+			}
+		}
+		return reply{n}
+	case "EXISTS":
+		var n int64
+		for _, key := range args {
+			if s.exists(key) {
+				n++
+			}
+		}
+		return reply{n}
+	case "EXPIRE", "PEXPIRE":
+		if len(args) != 2 {
+			return errArgs
+		}
+		n, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errSyntax
+		}
+		if !s.exists(args[0]) {
+			return reply{int64(0)}
+		}
+		unit := time.Second
+		if name == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+		s.expires[args[0]] = time.Now().Add(time.Duration(n) * unit)
+		return reply{int64(1)}
+	case "PTTL":
+		if len(args) != 1 {
+			return errArgs
+		}
+		if !s.exists(args[0]) {
+			return reply{int64(-2)}
+		}
+		at, found := s.expires[args[0]]
+		if !found {
+			return reply{int64(-1)}
+		}
+		return reply{time.Until(at).Milliseconds()}
+	case "HSET":
+		if len(args) < 3 || len(args)%2 != 1 {
+			return errArgs
+		}
+		h := s.hashes[args[0]]
+		if h == nil {
+			if s.exists(args[0]) {
+				return errWrongTyp
+			}
+			h = map[string]string{}
+			s.hashes[args[0]] = h
+		}
+		var added int64
+		for i := 1; i < len(args); i += 2 {
+			if _, found := h[args[i]]; !found {
+				added++
+			}
+			h[args[i]] = args[i+1]
+		}
+		return reply{added}
+	case "HGETALL":
+		if len(args) != 1 {
+			return errArgs
+		}
+		h := s.hashes[args[0]]
+		fields := make([]string, 0, len(h))
+		for field := range h {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		values := make([]string, 0, 2*len(h))
+		for _, field := range fields {
+			values = append(values, field, h[field])
+		}
+		return strs(values)
+	case "SADD":
+		if len(args) < 2 {
+			return errArgs
+		}
+		set := s.sets[args[0]]
+		if set == nil {
+			if s.exists(args[0]) {
+				return errWrongTyp
+			}
+			set = map[string]bool{}
+			s.sets[args[0]] = set
+		}
+		var added int64
+		for _, member := range args[1:] {
+			if !set[member] {
+				added++
+			}
+			set[member] = true
+		}
+		return reply{added}
+	case "SMEMBERS":
+		if len(args) != 1 {
+			return errArgs
+		}
+		members := make([]string, 0, len(s.sets[args[0]]))
+		for member := range s.sets[args[0]] {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+		return strs(members)
+	case "ZADD":
+		return s.zadd(args)
+	case "ZSCORE":
+		if len(args) != 2 {
+			return errArgs
+		}
+		score, found := s.zsets[args[0]][args[1]]
+		if !found {
+			return reply{nil}
+		}
+		return reply{formatScore(score)}
+	case "ZCARD":
+		if len(args) != 1 {
+			return errArgs
+		}
+		return reply{int64(len(s.zsets[args[0]]))}
+	case "ZRANGE":
+		return s.zrange(args)
+	case "ZPOPMIN":
+		if len(args) != 1 {
+			return errArgs
+		}
+		member, score, found := s.popMin(args[0])
+		if !found {
+			return reply{[]reply{}}
+		}
+		return strs([]string{member, formatScore(score)})
+	default:
+		return reply{fmt.Errorf("ERR unknown command '%s'", strings.ToLower(name))}
+	}
+}
+
+// keysOf returns the keys a command touches, so expired ones can be dropped first
+func keysOf(name string, args []string) []string {
+	switch name {
+	case "DEL", "EXISTS":
+		return args
+	case "PING", "SELECT":
+		return nil
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return args[:1]
+}
+
+func (s *Server) set(args []string) reply {
+	if len(args) < 2 {
+		return errArgs
+	}
+	key, val := args[0], args[1]
+	var ttl time.Duration
+	nx, xx, get := false, false, false
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "GET":
+			get = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return errSyntax
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return errSyntax
+			}
+			ttl = time.Duration(n) * time.Second
+			if strings.ToUpper(args[i]) == "PX" {
+				ttl = time.Duration(n) * time.Millisecond
+			}
+			i++
+		case "KEEPTTL":
+		default:
+			return errSyntax
+		}
+	}
+
+	old, hadOld := s.strings[key]
+	exists := s.exists(key)
+	if (nx && exists) || (xx && !exists) {
+		if get && hadOld {
+			return reply{old}
+		}
+		return reply{nil}
+	}
+
+	s.del(key)
+	s.strings[key] = val
+	if ttl > 0 {
+		s.expires[key] = time.Now().Add(ttl)
+	}
+	if get {
+		if hadOld {
+			return reply{old}
+		}
+		return reply{nil}
+	}
+	return ok
+}
+
+func (s *Server) zadd(args []string) reply {
+	if len(args) < 3 {
+		return errArgs
+	}
+	key := args[0]
+	i := 1
+	var nx, xx, lt, gt, ch bool
+flags:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "LT":
+			lt = true
+		case "GT":
+			gt = true
+		case "CH":
+			ch = true
+		default:
+			break flags
+		}
+	}
+	if (len(args)-i)%2 != 0 || i == len(args) || (nx && (xx || lt || gt)) || (lt && gt) {
+		return errSyntax
+	}
+
+	z := s.zsets[key]
+	if z == nil {
+		if s.exists(key) {
+			return errWrongTyp
+		}
+		z = map[string]float64{}
+	}
+
+	var added, changed int64
+	for ; i < len(args); i += 2 {
+		score, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			return reply{errors.New("ERR value is not a valid float")}
+		}
+		member := args[i+1]
+		current, found := z[member]
+		switch {
+		case found && nx, !found && xx:
+			continue
+		case found && lt && score >= current, found && gt && score <= current:
+			continue
+		}
+		if !found {
+			added++
+		} else if current != score {
+			changed++
+		}
+		z[member] = score
+	}
+	if len(z) > 0 {
+		s.zsets[key] = z
+	}
+
+	if ch {
+		return reply{added + changed}
+	}
+	return reply{added}
+}
+
+// sortedMembers returns a sorted set's members by score, then lexicographically
+func (s *Server) sortedMembers(key string) []string {
+	z := s.zsets[key]
+	members := make([]string, 0, len(z))
+	for member := range z {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if z[members[i]] != z[members[j]] {
+			return z[members[i]] < z[members[j]]
+		}
+		return members[i] < members[j]
+	})
+	return members
+}
+
+func (s *Server) zrange(args []string) reply {
+	if len(args) < 3 {
+		return errArgs
+	}
+	start, err1 := strconv.Atoi(args[1])
+	stop, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		return errSyntax
+	}
+	withScores := len(args) == 4 && strings.ToUpper(args[3]) == "WITHSCORES"
+	if len(args) > 4 || (len(args) == 4 && !withScores) {
+		return errSyntax
+	}
+
+	members := s.sortedMembers(args[0])
+	n := len(members)
+	if start < 0 {
+		start = int(math.Max(0, float64(n+start)))
+	}
+	if stop < 0 {
+		stop = n + stop
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+
+	var values []string
+	for i := start; i <= stop; i++ {
+		values = append(values, members[i])
+		if withScores {
+			values = append(values, formatScore(s.zsets[args[0]][members[i]]))
+		}
+	}
+	return strs(values)
+}
+
+func (s *Server) popMin(key string) (string, float64, bool) {
+	members := s.sortedMembers(key)
+	if len(members) == 0 {
+		return "", 0, false
+	}
+	score := s.zsets[key][members[0]]
+	delete(s.zsets[key], members[0])
+	if len(s.zsets[key]) == 0 {
+		delete(s.zsets, key)
+	}
+	return members[0], score, true
+}
+
+// bzpopmin pops from the first non-empty key, waiting up to the timeout for one to be filled
+func (s *Server) bzpopmin(args []string) reply {
+	if len(args) < 2 {
+		return errArgs
+	}
+	timeout, err := strconv.ParseFloat(args[len(args)-1], 64)
+	if err != nil {
+		return reply{errors.New("ERR timeout is not a float or out of range")}
+	}
+	keys := args[:len(args)-1]
+	deadline := time.Now().Add(time.Duration(timeout * float64(time.Second)))
+
+	for {
+		s.lk.Lock()
+		if s.closed {
+			s.lk.Unlock()
+			return reply{[]reply(nil)}
+		}
+		for _, key := range keys {
+			s.expire(key)
+			if member, score, found := s.popMin(key); found {
+				s.lk.Unlock()
+				return strs([]string{key, member, formatScore(score)})
+			}
+		}
+		s.lk.Unlock()
+
+		if timeout > 0 && !time.Now().Before(deadline) {
+			return reply{[]reply(nil)}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
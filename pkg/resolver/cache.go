@@ -0,0 +1,157 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+)
+
+var cacheLookupsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "dns_cache_lookups_total",
+	Help: "Total number of DNS lookups served by the resolver cache",
+}, []string{"result"})
+
+// Cache is an in-process cache in front of another Resolver.
+// Answers are kept for the TTL the upstream returned, clamped to [MinTTL, MaxTTL],
+// resolvers that can't report TTLs (like net.Resolver) are cached for DefaultTTL.
+// Not found answers are cached too, temporary failures never are.
+type Cache struct {
+	Resolver Resolver
+
+	MinTTL      time.Duration
+	MaxTTL      time.Duration
+	DefaultTTL  time.Duration
+	NegativeTTL time.Duration
+	MaxEntries  int
+
+	lk      sync.Mutex
+	entries map[string]*cacheEntry
+	group   singleflight.Group
+}
+
+type cacheEntry struct {
+	txts    []string
+	err     error
+	expires time.Time
+}
+
+func NewCache(resolver Resolver, maxEntries int) *Cache {
+	return &Cache{
+		Resolver: resolver,
+
+		MinTTL:      30 * time.Second,
+		MaxTTL:      time.Hour,
+		DefaultTTL:  5 * time.Minute,
+		NegativeTTL: time.Minute,
+		MaxEntries:  maxEntries,
+
+		entries: map[string]*cacheEntry{},
+	}
+}
+
+func (c *Cache) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txts, _, err := c.LookupTXTWithTTL(ctx, name)
+	return txts, err
+}
+
+// LookupTXTWithTTL returns the TXT records for name and how much longer the answer stays cached
+func (c *Cache) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	key := fqdn(name)
+
+	c.lk.Lock()
+	entry, ok := c.entries[key]
+	c.lk.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		cacheLookupsCounter.WithLabelValues("hit").Inc()
+		return entry.txts, time.Until(entry.expires), entry.err
+	}
+	cacheLookupsCounter.WithLabelValues("miss").Inc()
+
+	// Collapse concurrent lookups for the same name into a single upstream query
+	val, err, _ := c.group.Do(key, func() (interface{}, error) {
+		txts, ttl, err := c.lookup(ctx, name)
+		entry := &cacheEntry{txts: txts, err: err, expires: time.Now().Add(c.clamp(ttl, err))}
+		if cacheable(err) {
+			c.store(key, entry)
+		}
+		return entry, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entry = val.(*cacheEntry)
+	if !cacheable(entry.err) {
+		return entry.txts, 0, entry.err
+	}
+	return entry.txts, time.Until(entry.expires), entry.err
+}
+
+func (c *Cache) lookup(ctx context.Context, name string) ([]string, time.Duration, error) {
+	if r, ok := c.Resolver.(TTLResolver); ok {
+		return r.LookupTXTWithTTL(ctx, name)
+	}
+
+	txts, err := c.Resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return txts, c.NegativeTTL, err
+	}
+	return txts, c.DefaultTTL, nil
+}
+
+// clamp bounds a TTL, falling back to the negative TTL when a not found answer didn't come with one
+func (c *Cache) clamp(ttl time.Duration, err error) time.Duration {
+	if ttl == 0 && err != nil {
+		ttl = c.NegativeTTL
+	}
+	if ttl < c.MinTTL {
+		ttl = c.MinTTL
+	}
+	if ttl > c.MaxTTL {
+		ttl = c.MaxTTL
+	}
+	return ttl
+}
+
+func (c *Cache) store(key string, entry *cacheEntry) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+
+	if c.MaxEntries > 0 && len(c.entries) >= c.MaxEntries {
+		c.evict()
+	}
+	c.entries[key] = entry
+}
+
+// evict drops expired entries, and if that doesn't free enough room, arbitrary ones
+func (c *Cache) evict() {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+
+	for key := range c.entries {
+		if len(c.entries) < c.MaxEntries {
+			break
+		}
+		delete(c.entries, key)
+	}
+}
+
+// cacheable reports whether an answer is definitive, only successes and names without records are
+func cacheable(err error) bool {
+	if err == nil {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
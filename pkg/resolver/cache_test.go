@@ -0,0 +1,126 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingResolver counts the lookups that reach the resolver it wraps
+type countingResolver struct {
+	Resolver
+	lookups atomic.Int64
+}
+
+func (c *countingResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	c.lookups.Add(1)
+	return c.Resolver.LookupTXT(ctx, name)
+}
+
+func newTestCache(fake *Fake) (*Cache, *countingResolver) {
+	counting := &countingResolver{Resolver: fake}
+	cache := NewCache(counting, 100)
+	cache.MinTTL = 0
+	cache.DefaultTTL = 50 * time.Millisecond
+	cache.NegativeTTL = 50 * time.Millisecond
+	return cache, counting
+}
+
+func TestCacheExpiry(t *testing.T) {
+	fake := NewFake()
+	fake.SetTXT("_atproto.alice.test", "did=did:plc:one")
+	cache, counting := newTestCache(fake)
+	ctx := context.Background()
+
+	txts, ttl, err := cache.LookupTXTWithTTL(ctx, "_atproto.alice.test")
+	if err != nil || len(txts) != 1 || txts[0] != "did=did:plc:one" {
+		t.Fatalf("LookupTXTWithTTL = %q, %v", txts, err)
+	}
+	if ttl <= 0 || ttl > 50*time.Millisecond {
+		t.Errorf("ttl = %s, want up to the default TTL", ttl)
+	}
+
+	// Names are cached case insensitively and regardless of the trailing dot
+	fake.SetTXT("_atproto.alice.test", "did=did:plc:two")
+	txts, _ = cache.LookupTXT(ctx, "_ATPROTO.Alice.test.")
+	if len(txts) != 1 || txts[0] != "did=did:plc:one" {
+		t.Errorf("cached lookup = %q, want the first answer", txts)
+	}
+	if n := counting.lookups.Load(); n != 1 {
+		t.Errorf("resolver was queried %d times, want 1", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	txts, _ = cache.LookupTXT(ctx, "_atproto.alice.test")
+	if len(txts) != 1 || txts[0] != "did=did:plc:two" {
+		t.Errorf("lookup after expiry = %q, want the new answer", txts)
+	}
+	if n := counting.lookups.Load(); n != 2 {
+		t.Errorf("resolver was queried %d times, want 2", n)
+	}
+}
+
+func TestCacheNegative(t *testing.T) {
+	fake := NewFake()
+	cache, counting := newTestCache(fake)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.LookupTXT(ctx, "_atproto.nobody.test")
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			t.Fatalf("LookupTXT: %v, want not found", err)
+		}
+	}
+	if n := counting.lookups.Load(); n != 1 {
+		t.Errorf("resolver was queried %d times for a missing name, want 1", n)
+	}
+
+	// Once the negative answer expires the new record is seen
+	fake.SetTXT("_atproto.nobody.test", "did=did:plc:one")
+	time.Sleep(60 * time.Millisecond)
+	if txts, err := cache.LookupTXT(ctx, "_atproto.nobody.test"); err != nil || len(txts) != 1 {
+		t.Errorf("LookupTXT after the negative answer expired = %q, %v", txts, err)
+	}
+}
+
+func TestCacheSkipsTemporaryFailures(t *testing.T) {
+	fake := NewFake()
+	fake.SetError("_atproto.flaky.test", &net.DNSError{Err: "server misbehaving", Name: "_atproto.flaky.test", IsTemporary: true})
+	cache, counting := newTestCache(fake)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, ttl, err := cache.LookupTXTWithTTL(ctx, "_atproto.flaky.test")
+		if err == nil {
+			t.Fatal("LookupTXTWithTTL succeeded, want the temporary failure")
+		}
+		if ttl != 0 {
+			t.Errorf("ttl of a temporary failure = %s, want 0", ttl)
+		}
+	}
+	if n := counting.lookups.Load(); n != 2 {
+		t.Errorf("resolver was queried %d times, want every temporary failure retried", n)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	fake := NewFake()
+	cache, _ := newTestCache(fake)
+	cache.MaxEntries = 2
+	ctx := context.Background()
+
+	for _, name := range []string{"a.test", "b.test", "c.test", "d.test"} {
+		fake.SetTXT(name, "x")
+		cache.LookupTXT(ctx, name)
+	}
+
+	cache.lk.Lock()
+	defer cache.lk.Unlock()
+	if len(cache.entries) > 2 {
+		t.Errorf("cache holds %d entries, want at most 2", len(cache.entries))
+	}
+}
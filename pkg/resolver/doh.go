@@ -0,0 +1,83 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxDoHResponseSize caps how much of a DNS-over-HTTPS response we read, the largest possible DNS message
+const maxDoHResponseSize = 65535
+
+// DoH sends queries to a DNS-over-HTTPS endpoint (RFC 8484), e.g. https://cloudflare-dns.com/dns-query
+type DoH struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+func NewDoH(endpoint string) *DoH {
+	return &DoH{
+		Endpoint: endpoint,
+		Client: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			Timeout:   5 * time.Second,
+		},
+	}
+}
+
+func (d *DoH) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txts, _, err := d.LookupTXTWithTTL(ctx, name)
+	return txts, err
+}
+
+// LookupTXTWithTTL returns the TXT records for name and how long they may be cached.
+// Not found errors also carry a TTL, for negative caching.
+func (d *DoH) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	ctx, span := tracer.Start(ctx, "DoHLookupTXT")
+	defer span.End()
+	span.SetAttributes(attribute.String("name", name))
+
+	// The ID is always 0 so responses are cacheable by HTTP caches
+	query, err := buildTXTQuery(0, name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Endpoint, bytes.NewReader(query))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create DoH request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query DoH endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("failed to query DoH endpoint: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDoHResponseSize))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read DoH response: %w", err)
+	}
+
+	answer, err := parseTXTResponse(body, 0, name)
+	if answer == nil {
+		return nil, 0, err
+	}
+	if answer.truncated {
+		return nil, 0, fmt.Errorf("DoH response for %s was truncated", name)
+	}
+
+	return answer.txts, answer.ttl, err
+}
@@ -0,0 +1,91 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestDoH(t *testing.T) {
+	replies := map[string]dnsReply{
+		"_atproto.alice.test.":  {txts: [][]string{{"did=did:plc:ewvi7nxzyoun6zhxrhs64oiz"}}, ttl: 120},
+		"_atproto.nobody.test.": {rcode: dnsmessage.RCodeNameError, soaMinTTL: 30},
+		"_atproto.big.test.":    {truncated: true},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		query, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var p dnsmessage.Parser
+		h, err := p.Start(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Queries use ID 0 so HTTP caches can share responses
+		if h.ID != 0 {
+			http.Error(w, "query ID isn't 0", http.StatusBadRequest)
+			return
+		}
+		q, err := p.Question()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		reply, ok := replies[q.Name.String()]
+		if !ok {
+			http.Error(w, "unexpected name", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(buildReply(t, query, reply))
+	}))
+	defer srv.Close()
+
+	doh := NewDoH(srv.URL)
+	ctx := context.Background()
+
+	txts, ttl, err := doh.LookupTXTWithTTL(ctx, "_atproto.alice.test")
+	if err != nil {
+		t.Fatalf("LookupTXTWithTTL: %v", err)
+	}
+	if len(txts) != 1 || txts[0] != "did=did:plc:ewvi7nxzyoun6zhxrhs64oiz" {
+		t.Errorf("txts = %q", txts)
+	}
+	if ttl != 2*time.Minute {
+		t.Errorf("ttl = %s, want 2m", ttl)
+	}
+
+	_, ttl, err = doh.LookupTXTWithTTL(ctx, "_atproto.nobody.test")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Fatalf("LookupTXTWithTTL for a missing name: %v, want not found", err)
+	}
+	if ttl != 30*time.Second {
+		t.Errorf("negative ttl = %s, want 30s", ttl)
+	}
+
+	if _, _, err := doh.LookupTXTWithTTL(ctx, "_atproto.big.test"); err == nil {
+		t.Error("LookupTXTWithTTL accepted a truncated response")
+	}
+
+	if _, _, err := doh.LookupTXTWithTTL(ctx, "_atproto.unexpected.test"); err == nil {
+		t.Error("LookupTXTWithTTL accepted an HTTP error")
+	}
+}
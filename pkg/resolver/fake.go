@@ -0,0 +1,53 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake is an in-memory Resolver for tests, names that were never set are reported as not existing
+// and names set without records as existing without TXT records
+type Fake struct {
+	lk      sync.RWMutex
+	records map[string][]string
+	errs    map[string]error
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		records: map[string][]string{},
+		errs:    map[string]error{},
+	}
+}
+
+// SetTXT replaces the TXT records for name
+func (f *Fake) SetTXT(name string, txts ...string) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.records[fqdn(name)] = txts
+}
+
+// SetError makes lookups for name fail with err
+func (f *Fake) SetError(name string, err error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.errs[fqdn(name)] = err
+}
+
+func (f *Fake) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	f.lk.RLock()
+	defer f.lk.RUnlock()
+
+	key := fqdn(name)
+	if err, ok := f.errs[key]; ok {
+		return nil, err
+	}
+	txts, ok := f.records[key]
+	if !ok {
+		return nil, notFound(name)
+	}
+	if len(txts) == 0 {
+		return nil, noData(name)
+	}
+	return txts, nil
+}
@@ -0,0 +1,148 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// errMismatchedResponse is returned for messages that aren't a response to our query
+var errMismatchedResponse = errors.New("resolver: DNS response doesn't match query")
+
+// maxUDPSize is the EDNS0 payload size we advertise, the DNS flag day recommendation
+const maxUDPSize = 1232
+
+// buildTXTQuery encodes a recursive TXT query for name
+func buildTXTQuery(id uint16, name string) ([]byte, error) {
+	qname, err := dnsmessage.NewName(fqdn(name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNS name %q: %w", name, err)
+	}
+
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{ID: id, RecursionDesired: true})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: qname, Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(maxUDPSize, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, err
+	}
+
+	return b.Finish()
+}
+
+// txtResponse is a decoded answer to a TXT query
+type txtResponse struct {
+	txts      []string
+	ttl       time.Duration
+	truncated bool
+}
+
+// parseTXTResponse decodes the answer to a TXT query for name.
+// NXDOMAIN and empty answers are reported as not found, with the TTL taken from the SOA record when there is one,
+// and only NXDOMAIN is reported as the name not existing.
+func parseTXTResponse(msg []byte, id uint16, name string) (*txtResponse, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DNS response: %w", err)
+	}
+	if h.ID != id || !h.Response {
+		return nil, errMismatchedResponse
+	}
+	if h.Truncated {
+		return &txtResponse{truncated: true}, nil
+	}
+
+	switch h.RCode {
+	case dnsmessage.RCodeSuccess, dnsmessage.RCodeNameError:
+	default:
+		return nil, &net.DNSError{Err: fmt.Sprintf("server misbehaving: %s", h.RCode), Name: name, IsTemporary: true}
+	}
+
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil, fmt.Errorf("failed to parse DNS questions: %w", err)
+	}
+
+	resp := &txtResponse{}
+	var minTTL uint32
+	for {
+		ah, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DNS answer: %w", err)
+		}
+
+		// Answers may include the CNAME chain leading to the TXT records
+		if ah.Type != dnsmessage.TypeTXT {
+			if err := p.SkipAnswer(); err != nil {
+				return nil, fmt.Errorf("failed to parse DNS answer: %w", err)
+			}
+			continue
+		}
+
+		txt, err := p.TXTResource()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TXT record: %w", err)
+		}
+
+		// Long TXT records are split into several strings, join them the same way net.Resolver does
+		resp.txts = append(resp.txts, strings.Join(txt.TXT, ""))
+		if len(resp.txts) == 1 || ah.TTL < minTTL {
+			minTTL = ah.TTL
+		}
+	}
+
+	if h.RCode == dnsmessage.RCodeSuccess && len(resp.txts) > 0 {
+		resp.ttl = time.Duration(minTTL) * time.Second
+		return resp, nil
+	}
+
+	// Negative answers are cacheable for the SOA's minimum TTL (RFC 2308)
+	if err := p.SkipAllAnswers(); err == nil {
+		for {
+			ah, err := p.AuthorityHeader()
+			if err != nil {
+				break
+			}
+			if ah.Type != dnsmessage.TypeSOA {
+				if err := p.SkipAuthority(); err != nil {
+					break
+				}
+				continue
+			}
+			soa, err := p.SOAResource()
+			if err != nil {
+				break
+			}
+			ttl := ah.TTL
+			if soa.MinTTL < ttl {
+				ttl = soa.MinTTL
+			}
+			resp.ttl = time.Duration(ttl) * time.Second
+			break
+		}
+	}
+
+	if h.RCode == dnsmessage.RCodeNameError {
+		return resp, notFound(name)
+	}
+	return resp, noData(name)
+}
@@ -0,0 +1,132 @@
+package resolver
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsReply describes the answer a fake server gives to a TXT query
+type dnsReply struct {
+	rcode     dnsmessage.RCode
+	txts      [][]string
+	ttl       uint32
+	truncated bool
+	// soaMinTTL adds an SOA record to the authority section of negative answers
+	soaMinTTL uint32
+}
+
+// buildReply encodes reply as the response to query
+func buildReply(t *testing.T, query []byte, reply dnsReply) []byte {
+	t.Helper()
+
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+	q, err := p.Question()
+	if err != nil {
+		t.Fatalf("failed to parse question: %v", err)
+	}
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:                 h.ID,
+		Response:           true,
+		RecursionDesired:   h.RecursionDesired,
+		RecursionAvailable: true,
+		RCode:              reply.rcode,
+		Truncated:          reply.truncated,
+	})
+	b.EnableCompression()
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to build reply: %v", err)
+		}
+	}
+
+	must(b.StartQuestions())
+	must(b.Question(q))
+	must(b.StartAnswers())
+	for _, txt := range reply.txts {
+		must(b.TXTResource(dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: reply.ttl}, dnsmessage.TXTResource{TXT: txt}))
+	}
+	must(b.StartAuthorities())
+	if reply.soaMinTTL > 0 {
+		zone := dnsmessage.MustNewName("test.")
+		must(b.SOAResource(dnsmessage.ResourceHeader{Name: zone, Class: dnsmessage.ClassINET, TTL: 3600}, dnsmessage.SOAResource{
+			NS:      dnsmessage.MustNewName("ns.test."),
+			MBox:    dnsmessage.MustNewName("hostmaster.test."),
+			Serial:  1,
+			Refresh: 3600,
+			Retry:   600,
+			Expire:  86400,
+			MinTTL:  reply.soaMinTTL,
+		}))
+	}
+
+	msg, err := b.Finish()
+	if err != nil {
+		t.Fatalf("failed to build reply: %v", err)
+	}
+	return msg
+}
+
+func TestParseTXTResponse(t *testing.T) {
+	query, err := buildTXTQuery(7, "_atproto.alice.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Long records come split into strings and are joined back together, the shortest TTL wins
+	resp, err := parseTXTResponse(buildReply(t, query, dnsReply{
+		txts: [][]string{{"did=did:plc:", "ewvi7nxzyoun6zhxrhs64oiz"}},
+		ttl:  300,
+	}), 7, "_atproto.alice.test")
+	if err != nil {
+		t.Fatalf("parseTXTResponse: %v", err)
+	}
+	if len(resp.txts) != 1 || resp.txts[0] != "did=did:plc:ewvi7nxzyoun6zhxrhs64oiz" {
+		t.Errorf("txts = %q", resp.txts)
+	}
+	if resp.ttl != 300*time.Second {
+		t.Errorf("ttl = %s, want 5m", resp.ttl)
+	}
+
+	// Negative answers are cached for the SOA minimum
+	resp, err = parseTXTResponse(buildReply(t, query, dnsReply{rcode: dnsmessage.RCodeNameError, soaMinTTL: 60}), 7, "_atproto.alice.test")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Fatalf("parseTXTResponse: %v, want not found", err)
+	}
+	if resp.ttl != time.Minute {
+		t.Errorf("negative ttl = %s, want 1m", resp.ttl)
+	}
+	if !IsNXDomain(err) {
+		t.Errorf("IsNXDomain(%v) = false for NXDOMAIN", err)
+	}
+
+	// A name that exists without TXT records (NODATA) is not found, but isn't NXDOMAIN
+	resp, err = parseTXTResponse(buildReply(t, query, dnsReply{soaMinTTL: 60}), 7, "_atproto.alice.test")
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound || IsNXDomain(err) {
+		t.Fatalf("parseTXTResponse for NODATA: %v, want not found without NXDOMAIN", err)
+	}
+	if resp.ttl != time.Minute {
+		t.Errorf("NODATA ttl = %s, want 1m", resp.ttl)
+	}
+
+	// Responses to another query are rejected
+	if _, err := parseTXTResponse(buildReply(t, query, dnsReply{}), 8, "_atproto.alice.test"); !errors.Is(err, errMismatchedResponse) {
+		t.Errorf("parseTXTResponse with the wrong ID: %v, want errMismatchedResponse", err)
+	}
+
+	// Server failures are temporary
+	_, err = parseTXTResponse(buildReply(t, query, dnsReply{rcode: dnsmessage.RCodeServerFailure}), 7, "_atproto.alice.test")
+	if !errors.As(err, &dnsErr) || !dnsErr.IsTemporary {
+		t.Errorf("parseTXTResponse for SERVFAIL: %v, want a temporary error", err)
+	}
+}
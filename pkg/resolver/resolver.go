@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Resolver looks up the DNS records handle validation depends on.
+// *net.Resolver satisfies it, so net.DefaultResolver can be used directly.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// TTLResolver is a Resolver that also reports how long an answer may be cached
+type TTLResolver interface {
+	Resolver
+	LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error)
+}
+
+var tracer = otel.Tracer("bingo/resolver")
+
+// noSuchHost and noRecords are the messages of the not found errors for NXDOMAIN and for a name without TXT records
+const (
+	noSuchHost = "no such host"
+	noRecords  = "no TXT records"
+)
+
+// notFound builds the same error net.Resolver returns for a name that doesn't exist,
+// so callers can check IsNotFound regardless of the implementation
+func notFound(name string) error {
+	return &net.DNSError{Err: noSuchHost, Name: name, IsNotFound: true}
+}
+
+// noData builds the error for a name that exists but has no TXT records, it's also IsNotFound
+// like net.Resolver's, but IsNXDomain tells it apart
+func noData(name string) error {
+	return &net.DNSError{Err: noRecords, Name: name, IsNotFound: true}
+}
+
+// IsNXDomain reports whether err says the name doesn't exist at all, rather than having no TXT records.
+// net.Resolver reports both as "no such host", so through it a name without records counts as not existing.
+func IsNXDomain(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound && dnsErr.Err != noRecords
+}
+
+// fqdn returns name in the fully qualified, lowercase form used on the wire and as a cache key
+func fqdn(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
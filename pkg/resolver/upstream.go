@@ -0,0 +1,157 @@
+package resolver
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Upstream sends queries directly to a fixed set of DNS servers instead of the system resolver.
+// Servers are tried in order, moving on to the next one when a server fails or times out.
+type Upstream struct {
+	Servers []string
+	Timeout time.Duration
+
+	dialer net.Dialer
+}
+
+// NewUpstream creates a resolver for the given servers, a port of 53 is assumed when one isn't given
+func NewUpstream(servers []string) (*Upstream, error) {
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no DNS servers configured")
+	}
+
+	addrs := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		addrs = append(addrs, server)
+	}
+
+	return &Upstream{
+		Servers: addrs,
+		Timeout: 2 * time.Second,
+	}, nil
+}
+
+func (u *Upstream) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txts, _, err := u.LookupTXTWithTTL(ctx, name)
+	return txts, err
+}
+
+// LookupTXTWithTTL returns the TXT records for name and how long they may be cached.
+// Not found errors also carry a TTL, for negative caching.
+func (u *Upstream) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	ctx, span := tracer.Start(ctx, "UpstreamLookupTXT")
+	defer span.End()
+	span.SetAttributes(attribute.String("name", name))
+
+	var lastErr error
+	for _, server := range u.Servers {
+		resp, err := u.exchange(ctx, server, name)
+		var dnsErr *net.DNSError
+		if err == nil || (errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+			span.SetAttributes(attribute.String("server", server))
+			return resp.txts, resp.ttl, err
+		}
+		lastErr = fmt.Errorf("failed to query %s: %w", server, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, 0, lastErr
+}
+
+// exchange queries a single server over UDP, retrying over TCP if the answer was truncated
+func (u *Upstream) exchange(ctx context.Context, server string, name string) (*txtResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.Timeout)
+	defer cancel()
+
+	id := uint16(rand.Uint32())
+	query, err := buildTXTQuery(id, name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := u.exchangeUDP(ctx, server, query, id, name)
+	if err != nil && resp == nil {
+		return nil, err
+	}
+	if resp.truncated {
+		return u.exchangeTCP(ctx, server, query, id, name)
+	}
+
+	return resp, err
+}
+
+func (u *Upstream) exchangeUDP(ctx context.Context, server string, query []byte, id uint16, name string) (*txtResponse, error) {
+	conn, err := u.dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxUDPSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := parseTXTResponse(buf[:n], id, name)
+		if errors.Is(err, errMismatchedResponse) {
+			// Ignore stray or spoofed datagrams that don't answer our query
+			continue
+		}
+		return resp, err
+	}
+}
+
+func (u *Upstream) exchangeTCP(ctx context.Context, server string, query []byte, id uint16, name string) (*txtResponse, error) {
+	conn, err := u.dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// Messages over TCP are prefixed with their length
+	msg := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(msg, uint16(len(query)))
+	copy(msg[2:], query)
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+
+	return parseTXTResponse(buf, id, name)
+}
@@ -0,0 +1,144 @@
+package resolver
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startUDPServer answers every query on a local UDP port with reply, or never answers if reply is nil
+func startUDPServer(t *testing.T, reply *dnsReply) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply == nil {
+				continue
+			}
+			conn.WriteTo(buildReply(t, buf[:n], *reply), addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// startTCPServer answers length prefixed queries on a local TCP port with reply
+func startTCPServer(t *testing.T, addr string, reply dnsReply) {
+	t.Helper()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var length [2]byte
+				if _, err := io.ReadFull(conn, length[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				msg := buildReply(t, query, reply)
+				out := make([]byte, 2+len(msg))
+				binary.BigEndian.PutUint16(out, uint16(len(msg)))
+				copy(out[2:], msg)
+				conn.Write(out)
+			}()
+		}
+	}()
+}
+
+func TestUpstreamFallback(t *testing.T) {
+	silent := startUDPServer(t, nil)
+	failing := startUDPServer(t, &dnsReply{rcode: dnsmessage.RCodeServerFailure})
+	working := startUDPServer(t, &dnsReply{txts: [][]string{{"did=did:plc:one"}}, ttl: 60})
+
+	u, err := NewUpstream([]string{silent, failing, working})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Timeout = 100 * time.Millisecond
+
+	txts, ttl, err := u.LookupTXTWithTTL(context.Background(), "_atproto.alice.test")
+	if err != nil {
+		t.Fatalf("LookupTXTWithTTL: %v", err)
+	}
+	if len(txts) != 1 || txts[0] != "did=did:plc:one" || ttl != time.Minute {
+		t.Errorf("LookupTXTWithTTL = %q, %s", txts, ttl)
+	}
+}
+
+func TestUpstreamNotFoundIsFinal(t *testing.T) {
+	missing := startUDPServer(t, &dnsReply{rcode: dnsmessage.RCodeNameError, soaMinTTL: 30})
+	working := startUDPServer(t, &dnsReply{txts: [][]string{{"did=did:plc:one"}}, ttl: 60})
+
+	u, err := NewUpstream([]string{missing, working})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A server saying the name doesn't exist is an answer, the next server isn't asked
+	_, ttl, err := u.LookupTXTWithTTL(context.Background(), "_atproto.nobody.test")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Fatalf("LookupTXTWithTTL: %v, want not found", err)
+	}
+	if ttl != 30*time.Second {
+		t.Errorf("negative ttl = %s, want 30s", ttl)
+	}
+}
+
+func TestUpstreamAllFail(t *testing.T) {
+	u, err := NewUpstream([]string{startUDPServer(t, nil), startUDPServer(t, nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Timeout = 50 * time.Millisecond
+
+	if _, err := u.LookupTXT(context.Background(), "_atproto.alice.test"); err == nil {
+		t.Fatal("LookupTXT succeeded with no server answering")
+	}
+}
+
+func TestUpstreamTruncatedRetriesOverTCP(t *testing.T) {
+	addr := startUDPServer(t, &dnsReply{truncated: true})
+	startTCPServer(t, addr, dnsReply{txts: [][]string{{"did=did:plc:one"}}, ttl: 60})
+
+	u, err := NewUpstream([]string{addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txts, err := u.LookupTXT(context.Background(), "_atproto.alice.test")
+	if err != nil {
+		t.Fatalf("LookupTXT: %v", err)
+	}
+	if len(txts) != 1 || txts[0] != "did=did:plc:one" {
+		t.Errorf("txts = %q", txts)
+	}
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetCursor returns the stored value of a named ingestion cursor
+func (s *Store) GetCursor(ctx context.Context, name string) (string, error) {
+	ctx, span := tracer.Start(ctx, "GetCursor")
+	defer span.End()
+	span.SetAttributes(attribute.String("name", name))
+
+	value, err := s.Queries.GetCursor(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("bingo: failed to get cursor: %w", err)
+	}
+
+	return value, nil
+}
+
+func setCursor(ctx context.Context, queries *store_queries.Queries, name string, value string) error {
+	err := queries.SetCursor(ctx, store_queries.SetCursorParams{
+		Name:  name,
+		Value: value,
+	})
+	if err != nil {
+		return fmt.Errorf("bingo: failed to set cursor: %w", err)
+	}
+
+	return nil
+}
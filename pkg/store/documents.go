@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+	"github.com/ericvolp12/bingo/pkg/syntax"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Service is a service endpoint advertised by a DID
+type Service struct {
+	Type     string `json:"type"`
+	Endpoint string `json:"endpoint"`
+}
+
+// DIDDocument is the normalized identity data for a DID, in the shape PLC operations use
+type DIDDocument struct {
+	Did                 string             `json:"did"`
+	AlsoKnownAs         []string           `json:"alsoKnownAs"`
+	VerificationMethods map[string]string  `json:"verificationMethods"`
+	RotationKeys        []string           `json:"rotationKeys"`
+	Services            map[string]Service `json:"services"`
+	ChainInvalid        bool               `json:"chainInvalid"`
+	ChainError          string             `json:"chainError"`
+	UpdatedAt           time.Time          `json:"updatedAt"`
+}
+
+// PDSEndpoint returns the DID's atproto PDS endpoint or an empty string if it doesn't advertise one
+func (doc *DIDDocument) PDSEndpoint() string {
+	return doc.Services["atproto_pds"].Endpoint
+}
+
+// Handle returns the normalized handle claimed by the document's first at:// alias
+// or an empty string if it doesn't claim a valid one
+func (doc *DIDDocument) Handle() string {
+	for _, aka := range doc.AlsoKnownAs {
+		if strings.HasPrefix(aka, "at://") {
+			handle, err := syntax.NormalizeHandle(strings.TrimPrefix(aka, "at://"))
+			if err != nil {
+				return ""
+			}
+			return handle
+		}
+	}
+	return ""
+}
+
+// UpdateDIDDocument replaces the stored identity data for a DID
+func (s *Store) UpdateDIDDocument(ctx context.Context, doc *DIDDocument) error {
+	ctx, span := tracer.Start(ctx, "UpdateDIDDocument")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", doc.Did))
+
+	verificationMethods, err := json.Marshal(doc.VerificationMethods)
+	if err != nil {
+		return fmt.Errorf("bingo: failed to marshal verification methods: %w", err)
+	}
+
+	services, err := json.Marshal(doc.Services)
+	if err != nil {
+		return fmt.Errorf("bingo: failed to marshal services: %w", err)
+	}
+
+	err = s.Queries.UpsertDIDDocument(ctx, store_queries.UpsertDIDDocumentParams{
+		Did:                 doc.Did,
+		AlsoKnownAs:         nonNil(doc.AlsoKnownAs),
+		VerificationMethods: verificationMethods,
+		RotationKeys:        nonNil(doc.RotationKeys),
+		Services:            services,
+	})
+	if err != nil {
+		return fmt.Errorf("bingo: failed to update DID document: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDIDDocuments replaces the stored identity data for many DIDs at once
+func (s *Store) UpdateDIDDocuments(ctx context.Context, docs []*DIDDocument) error {
+	ctx, span := tracer.Start(ctx, "UpdateDIDDocuments")
+	defer span.End()
+	span.SetAttributes(attribute.Int("documents", len(docs)))
+
+	return updateDIDDocuments(ctx, s.Queries, docs)
+}
+
+func updateDIDDocuments(ctx context.Context, queries *store_queries.Queries, docs []*DIDDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	params := store_queries.UpsertDIDDocumentsParams{}
+	for _, doc := range docs {
+		alsoKnownAs, err := json.Marshal(nonNil(doc.AlsoKnownAs))
+		if err != nil {
+			return fmt.Errorf("bingo: failed to marshal also known as: %w", err)
+		}
+		verificationMethods, err := json.Marshal(doc.VerificationMethods)
+		if err != nil {
+			return fmt.Errorf("bingo: failed to marshal verification methods: %w", err)
+		}
+		rotationKeys, err := json.Marshal(nonNil(doc.RotationKeys))
+		if err != nil {
+			return fmt.Errorf("bingo: failed to marshal rotation keys: %w", err)
+		}
+		services, err := json.Marshal(doc.Services)
+		if err != nil {
+			return fmt.Errorf("bingo: failed to marshal services: %w", err)
+		}
+
+		params.Dids = append(params.Dids, doc.Did)
+		params.AlsoKnownAs = append(params.AlsoKnownAs, string(alsoKnownAs))
+		params.VerificationMethods = append(params.VerificationMethods, string(verificationMethods))
+		params.RotationKeys = append(params.RotationKeys, string(rotationKeys))
+		params.Services = append(params.Services, string(services))
+	}
+
+	err := queries.UpsertDIDDocuments(ctx, params)
+	if err != nil {
+		return fmt.Errorf("bingo: failed to update DID documents: %w", err)
+	}
+
+	return nil
+}
+
+func nonNil(vals []string) []string {
+	if vals == nil {
+		return []string{}
+	}
+	return vals
+}
+
+// GetDIDDocument returns the stored identity data for a DID
+func (s *Store) GetDIDDocument(ctx context.Context, did string) (*DIDDocument, error) {
+	ctx, span := tracer.Start(ctx, "GetDIDDocument")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	dbDoc, err := s.Queries.GetDIDDocument(ctx, did)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("bingo: failed to get DID document: %w", err)
+	}
+
+	doc := &DIDDocument{
+		Did:          dbDoc.Did,
+		AlsoKnownAs:  dbDoc.AlsoKnownAs,
+		RotationKeys: dbDoc.RotationKeys,
+		ChainInvalid: dbDoc.ChainInvalid,
+		ChainError:   dbDoc.ChainError,
+		UpdatedAt:    dbDoc.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(dbDoc.VerificationMethods, &doc.VerificationMethods); err != nil {
+		return nil, fmt.Errorf("bingo: failed to unmarshal verification methods: %w", err)
+	}
+
+	if err := json.Unmarshal(dbDoc.Services, &doc.Services); err != nil {
+		return nil, fmt.Errorf("bingo: failed to unmarshal services: %w", err)
+	}
+
+	return doc, nil
+}
+
+func flagDIDChainInvalid(ctx context.Context, queries *store_queries.Queries, did string, reason string) error {
+	err := queries.FlagDIDChainInvalid(ctx, store_queries.FlagDIDChainInvalidParams{
+		Did:        did,
+		ChainError: reason,
+	})
+	if err != nil {
+		return fmt.Errorf("bingo: failed to flag DID chain: %w", err)
+	}
+
+	return nil
+}
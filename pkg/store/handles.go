@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+	"github.com/ericvolp12/bingo/pkg/syntax"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// normalizeHandlesPageSize is how many rows with unnormalized handles are rewritten at once
+const normalizeHandlesPageSize = 1000
+
+// NormalizeHandles rewrites handles stored before handles were normalized, in entries and in the
+// operation log, to the form syntax.NormalizeHandle gives them so lookups and handle history find them.
+// Handles that can't be normalized are logged and left as they are. It's safe to run more than once.
+// It returns the number of entries and operations that were rewritten.
+func (s *Store) NormalizeHandles(ctx context.Context) (int, int, error) {
+	ctx, span := tracer.Start(ctx, "NormalizeHandles")
+	defer span.End()
+
+	entries, err := s.normalizeEntryHandles(ctx)
+	if err != nil {
+		return entries, 0, err
+	}
+
+	operations, err := s.normalizeOperationHandles(ctx)
+	if err != nil {
+		return entries, operations, err
+	}
+
+	span.SetAttributes(attribute.Int("entries", entries), attribute.Int("operations", operations))
+	return entries, operations, nil
+}
+
+func (s *Store) normalizeEntryHandles(ctx context.Context) (int, error) {
+	renamed := 0
+	after := ""
+	for {
+		rows, err := s.Queries.GetUnnormalizedEntryHandles(ctx, store_queries.GetUnnormalizedEntryHandlesParams{
+			After: after,
+			Limit: normalizeHandlesPageSize,
+		})
+		if err != nil {
+			return renamed, fmt.Errorf("bingo: failed to list entry handles: %w", err)
+		}
+		if len(rows) == 0 {
+			return renamed, nil
+		}
+		after = rows[len(rows)-1].Did
+
+		params := store_queries.RenameEntryHandlesParams{}
+		for _, row := range rows {
+			handle, ok := normalizedHandle(row.Did, row.Handle)
+			if !ok {
+				continue
+			}
+			params.Dids = append(params.Dids, row.Did)
+			params.OldHandles = append(params.OldHandles, row.Handle)
+			params.NewHandles = append(params.NewHandles, handle)
+		}
+		if len(params.Dids) == 0 {
+			continue
+		}
+
+		dbEntries, err := s.Queries.RenameEntryHandles(ctx, params)
+		if err != nil {
+			return renamed, fmt.Errorf("bingo: failed to rename entry handles: %w", err)
+		}
+		renamed += len(dbEntries)
+
+		// Drop the keys of the old handles, the warmup only ever wrote normalized ones but older servers didn't
+		pipeline := s.Redis.Pipeline()
+		for _, handle := range params.OldHandles {
+			pipeline.Del(ctx, fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byHandlePrefix, handle))
+		}
+		if _, err := pipeline.Exec(ctx); err != nil {
+			return renamed, fmt.Errorf("bingo: failed to execute pipeline: %w", err)
+		}
+
+		entries := make([]*Entry, 0, len(dbEntries))
+		for _, dbEntry := range dbEntries {
+			entries = append(entries, entryFromDB(dbEntry))
+		}
+		if err := s.cacheEntries(ctx, entries); err != nil {
+			return renamed, err
+		}
+	}
+}
+
+func (s *Store) normalizeOperationHandles(ctx context.Context) (int, error) {
+	renamed := 0
+	after := ""
+	for {
+		rows, err := s.Queries.GetUnnormalizedOperationHandles(ctx, store_queries.GetUnnormalizedOperationHandlesParams{
+			After: after,
+			Limit: normalizeHandlesPageSize,
+		})
+		if err != nil {
+			return renamed, fmt.Errorf("bingo: failed to list operation handles: %w", err)
+		}
+		if len(rows) == 0 {
+			return renamed, nil
+		}
+		after = rows[len(rows)-1].Cid
+
+		params := store_queries.RenameOperationHandlesParams{}
+		for _, row := range rows {
+			handle, ok := normalizedHandle(row.Cid, row.Handle)
+			if !ok {
+				continue
+			}
+			params.Cids = append(params.Cids, row.Cid)
+			params.OldHandles = append(params.OldHandles, row.Handle)
+			params.NewHandles = append(params.NewHandles, handle)
+		}
+		if len(params.Cids) == 0 {
+			continue
+		}
+
+		if err := s.Queries.RenameOperationHandles(ctx, params); err != nil {
+			return renamed, fmt.Errorf("bingo: failed to rename operation handles: %w", err)
+		}
+		renamed += len(params.Cids)
+	}
+}
+
+// normalizedHandle returns the normalized form of a stored handle if it differs from it,
+// id names the row in the log line for handles that can't be normalized
+func normalizedHandle(id string, handle string) (string, bool) {
+	normalized, err := syntax.NormalizeHandle(handle)
+	if err != nil {
+		log.Printf("leaving invalid handle of %s as it is: %+v", id, err)
+		return "", false
+	}
+	return normalized, normalized != handle
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Operation is a single PLC operation as it appears in the PLC export
+type Operation struct {
+	Cid       string          `json:"cid"`
+	Did       string          `json:"did"`
+	Handle    string          `json:"handle"`
+	Operation json.RawMessage `json:"operation"`
+	Nullified bool            `json:"nullified"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// RecordOperations persists a batch of PLC operations to the operation log.
+// It returns the CIDs that were new or changed, so already-applied operations can be skipped.
+func (s *Store) RecordOperations(ctx context.Context, ops []*Operation) (map[string]bool, error) {
+	ctx, span := tracer.Start(ctx, "RecordOperations")
+	defer span.End()
+	span.SetAttributes(attribute.Int("operations", len(ops)))
+
+	return recordOperations(ctx, s.Queries, ops)
+}
+
+func recordOperations(ctx context.Context, queries *store_queries.Queries, ops []*Operation) (map[string]bool, error) {
+	params := store_queries.UpsertPLCOperationsParams{}
+	for _, op := range ops {
+		params.Cids = append(params.Cids, op.Cid)
+		params.Dids = append(params.Dids, op.Did)
+		params.Handles = append(params.Handles, op.Handle)
+		params.Operations = append(params.Operations, string(op.Operation))
+		params.Nullified = append(params.Nullified, op.Nullified)
+		params.CreatedAts = append(params.CreatedAts, op.CreatedAt)
+	}
+
+	cids, err := queries.UpsertPLCOperations(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("bingo: failed to record operations: %w", err)
+	}
+
+	changed := make(map[string]bool, len(cids))
+	for _, cid := range cids {
+		changed[cid] = true
+	}
+
+	return changed, nil
+}
+
+// GetOperation returns a single PLC operation from the operation log by CID
+func (s *Store) GetOperation(ctx context.Context, cid string) (*Operation, error) {
+	ctx, span := tracer.Start(ctx, "GetOperation")
+	defer span.End()
+	span.SetAttributes(attribute.String("cid", cid))
+
+	return getOperation(ctx, s.Queries, cid)
+}
+
+func getOperation(ctx context.Context, queries *store_queries.Queries, cid string) (*Operation, error) {
+	dbOp, err := queries.GetPLCOperationByCID(ctx, cid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("bingo: failed to get operation: %w", err)
+	}
+
+	return &Operation{
+		Cid:       dbOp.Cid,
+		Did:       dbOp.Did,
+		Handle:    dbOp.Handle,
+		Operation: dbOp.Operation,
+		Nullified: dbOp.Nullified,
+		CreatedAt: dbOp.CreatedAt,
+	}, nil
+}
+
+// GetOperationsForDID returns the full operation log for a DID, oldest first
+func (s *Store) GetOperationsForDID(ctx context.Context, did string) ([]*Operation, error) {
+	ctx, span := tracer.Start(ctx, "GetOperationsForDID")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	return getOperationsForDID(ctx, s.Queries, did)
+}
+
+func getOperationsForDID(ctx context.Context, queries *store_queries.Queries, did string) ([]*Operation, error) {
+	dbOps, err := queries.GetPLCOperationsByDID(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("bingo: failed to get operations: %w", err)
+	}
+
+	ops := make([]*Operation, 0, len(dbOps))
+	for _, dbOp := range dbOps {
+		ops = append(ops, &Operation{
+			Cid:       dbOp.Cid,
+			Did:       dbOp.Did,
+			Handle:    dbOp.Handle,
+			Operation: dbOp.Operation,
+			Nullified: dbOp.Nullified,
+			CreatedAt: dbOp.CreatedAt,
+		})
+	}
+
+	return ops, nil
+}
+
+// HandleClaim records a DID claiming a handle in a PLC operation
+type HandleClaim struct {
+	Did       string    `json:"did"`
+	Handle    string    `json:"handle"`
+	Cid       string    `json:"cid"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetHandleHistoryByDID returns each handle a DID has claimed, oldest first.
+// Consecutive operations that keep the same handle are collapsed into the first claim.
+func (s *Store) GetHandleHistoryByDID(ctx context.Context, did string) ([]*HandleClaim, error) {
+	ctx, span := tracer.Start(ctx, "GetHandleHistoryByDID")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	dbOps, err := s.Queries.GetHandleClaimsByDID(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("bingo: failed to get handle claims: %w", err)
+	}
+
+	claims := []*HandleClaim{}
+	for _, dbOp := range dbOps {
+		if len(claims) > 0 && claims[len(claims)-1].Handle == dbOp.Handle {
+			continue
+		}
+		claims = append(claims, &HandleClaim{
+			Did:       dbOp.Did,
+			Handle:    dbOp.Handle,
+			Cid:       dbOp.Cid,
+			CreatedAt: dbOp.CreatedAt,
+		})
+	}
+
+	return claims, nil
+}
+
+// GetHandleHistoryByHandle returns every DID that has claimed a handle, ordered by when they first claimed it
+func (s *Store) GetHandleHistoryByHandle(ctx context.Context, handle string) ([]*HandleClaim, error) {
+	ctx, span := tracer.Start(ctx, "GetHandleHistoryByHandle")
+	defer span.End()
+	span.SetAttributes(attribute.String("handle", handle))
+
+	dbOps, err := s.Queries.GetHandleClaimsByHandle(ctx, handle)
+	if err != nil {
+		return nil, fmt.Errorf("bingo: failed to get handle claims: %w", err)
+	}
+
+	seen := map[string]bool{}
+	claims := []*HandleClaim{}
+	for _, dbOp := range dbOps {
+		if seen[dbOp.Did] {
+			continue
+		}
+		seen[dbOp.Did] = true
+		claims = append(claims, &HandleClaim{
+			Did:       dbOp.Did,
+			Handle:    dbOp.Handle,
+			Cid:       dbOp.Cid,
+			CreatedAt: dbOp.CreatedAt,
+		})
+	}
+
+	return claims, nil
+}
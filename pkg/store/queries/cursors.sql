@@ -0,0 +1,10 @@
+-- name: GetCursor :one
+SELECT value
+FROM cursors
+WHERE name = $1;
+-- name: SetCursor :exec
+INSERT INTO cursors (name, value, updated_at)
+VALUES ($1, $2, NOW()) ON CONFLICT (name) DO
+UPDATE
+SET value = EXCLUDED.value,
+    updated_at = EXCLUDED.updated_at;
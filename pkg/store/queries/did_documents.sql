@@ -0,0 +1,64 @@
+-- name: UpsertDIDDocument :exec
+INSERT INTO did_documents (
+        did,
+        also_known_as,
+        verification_methods,
+        rotation_keys,
+        services,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4, $5, NOW()) ON CONFLICT (did) DO
+UPDATE
+SET also_known_as = EXCLUDED.also_known_as,
+    verification_methods = EXCLUDED.verification_methods,
+    rotation_keys = EXCLUDED.rotation_keys,
+    services = EXCLUDED.services,
+    updated_at = EXCLUDED.updated_at
+WHERE did_documents.did = EXCLUDED.did;
+-- name: GetDIDDocument :one
+SELECT *
+FROM did_documents
+WHERE did = $1;
+-- name: DeleteDIDDocument :exec
+DELETE FROM did_documents
+WHERE did = $1;
+-- name: FlagDIDChainInvalid :exec
+INSERT INTO did_documents (did, chain_invalid, chain_error)
+VALUES ($1, TRUE, $2) ON CONFLICT (did) DO
+UPDATE
+SET chain_invalid = TRUE,
+    chain_error = EXCLUDED.chain_error
+WHERE did_documents.did = EXCLUDED.did;
+-- name: UpsertDIDDocuments :exec
+INSERT INTO did_documents (
+        did,
+        also_known_as,
+        verification_methods,
+        rotation_keys,
+        services,
+        updated_at
+    )
+SELECT docs.did,
+    ARRAY(
+        SELECT jsonb_array_elements_text(docs.also_known_as::jsonb)
+    ),
+    docs.verification_methods::jsonb,
+    ARRAY(
+        SELECT jsonb_array_elements_text(docs.rotation_keys::jsonb)
+    ),
+    docs.services::jsonb,
+    NOW()
+FROM (
+        SELECT unnest(sqlc.arg('dids')::text []) AS did,
+            unnest(sqlc.arg('also_known_as')::text []) AS also_known_as,
+            unnest(sqlc.arg('verification_methods')::text []) AS verification_methods,
+            unnest(sqlc.arg('rotation_keys')::text []) AS rotation_keys,
+            unnest(sqlc.arg('services')::text []) AS services
+    ) AS docs ON CONFLICT (did) DO
+UPDATE
+SET also_known_as = EXCLUDED.also_known_as,
+    verification_methods = EXCLUDED.verification_methods,
+    rotation_keys = EXCLUDED.rotation_keys,
+    services = EXCLUDED.services,
+    updated_at = EXCLUDED.updated_at
+WHERE did_documents.did = EXCLUDED.did;
@@ -3,7 +3,14 @@ INSERT INTO entries (did, handle, is_valid, last_checked_time)
 VALUES ($1, $2, $3, $4) ON CONFLICT (did) DO
 UPDATE
 SET handle = EXCLUDED.handle,
-    updated_at = EXCLUDED.updated_at
+    updated_at = EXCLUDED.updated_at,
+    next_check_time = CASE
+        WHEN entries.handle = EXCLUDED.handle THEN entries.next_check_time
+    END,
+    check_streak = CASE
+        WHEN entries.handle = EXCLUDED.handle THEN entries.check_streak
+        ELSE 0
+    END
 WHERE entries.did = EXCLUDED.did;
 -- name: GetEntryByDID :one
 SELECT *
@@ -13,20 +20,111 @@ WHERE did = $1;
 SELECT *
 FROM entries
 WHERE handle = $1;
--- name: GetEntriesForValidation :many
-SELECT *
-from entries
-WHERE last_checked_time is NULL
-    OR last_checked_time < $1
-ORDER BY did
-LIMIT $2;
--- name: UpdateEntriesValidation :exec
+-- name: ClaimEntriesForValidation :many
+UPDATE entries
+SET lease_owner = sqlc.arg('lease_owner'),
+    lease_expires_at = NOW() + sqlc.arg('lease_seconds')::float8 * INTERVAL '1 second'
+WHERE did IN (
+        SELECT did
+        FROM entries
+        WHERE (
+                next_check_time IS NULL
+                OR next_check_time <= NOW()
+            )
+            AND (
+                lease_expires_at IS NULL
+                OR lease_expires_at <= NOW()
+            )
+        ORDER BY next_check_time NULLS FIRST
+        LIMIT sqlc.arg('limit')
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING *;
+-- name: UpdateEntriesValidation :many
 UPDATE entries
-SET last_checked_time = $1,
-    is_valid = $2
-WHERE did = ANY(sqlc.arg('dids')::text []);
+SET last_checked_time = sqlc.arg('last_checked_time'),
+    is_valid = v.is_valid,
+    validation_status = v.validation_status,
+    validation_detail = v.validation_detail,
+    next_check_time = v.next_check_time,
+    check_streak = v.check_streak,
+    lease_owner = CASE
+        WHEN sqlc.arg('lease_owner')::text = '' THEN entries.lease_owner
+        ELSE ''
+    END,
+    lease_expires_at = CASE
+        WHEN sqlc.arg('lease_owner')::text = '' THEN entries.lease_expires_at
+    END
+FROM (
+        SELECT unnest(sqlc.arg('dids')::text []) AS did,
+            unnest(sqlc.arg('handles')::text []) AS handle,
+            unnest(sqlc.arg('is_valids')::boolean []) AS is_valid,
+            unnest(sqlc.arg('statuses')::text []) AS validation_status,
+            unnest(sqlc.arg('details')::text []) AS validation_detail,
+            unnest(sqlc.arg('next_check_times')::timestamptz []) AS next_check_time,
+            unnest(sqlc.arg('check_streaks')::integer []) AS check_streak
+    ) AS v
+WHERE entries.did = v.did
+    AND entries.handle = v.handle
+    AND (
+        sqlc.arg('lease_owner')::text = ''
+        OR entries.lease_owner = sqlc.arg('lease_owner')::text
+    )
+RETURNING entries.did,
+    entries.handle;
+-- name: RenewEntryLeases :exec
+UPDATE entries
+SET lease_expires_at = NOW() + sqlc.arg('lease_seconds')::float8 * INTERVAL '1 second'
+WHERE did = ANY(sqlc.arg('dids')::text [])
+    AND lease_owner = sqlc.arg('lease_owner');
+-- name: ReleaseEntryLeases :exec
+UPDATE entries
+SET lease_owner = '',
+    lease_expires_at = NULL
+WHERE did = ANY(sqlc.arg('dids')::text [])
+    AND lease_owner = sqlc.arg('lease_owner');
 -- name: GetEntries :many
 SELECT *
 FROM entries
 ORDER BY did
 LIMIT $1 OFFSET $2;
+-- name: DeleteEntry :exec
+DELETE FROM entries
+WHERE did = $1;
+-- name: UpdateEntries :exec
+INSERT INTO entries (did, handle, is_valid)
+SELECT unnest(sqlc.arg('dids')::text []),
+    unnest(sqlc.arg('handles')::text []),
+    FALSE ON CONFLICT (did) DO
+UPDATE
+SET handle = EXCLUDED.handle,
+    updated_at = EXCLUDED.updated_at,
+    next_check_time = CASE
+        WHEN entries.handle = EXCLUDED.handle
+        AND entries.validation_status NOT IN ('unclaimed', 'document_error') THEN entries.next_check_time
+    END,
+    check_streak = CASE
+        WHEN entries.handle = EXCLUDED.handle THEN entries.check_streak
+        ELSE 0
+    END
+WHERE entries.did = EXCLUDED.did;
+-- name: GetUnnormalizedEntryHandles :many
+SELECT did,
+    handle
+FROM entries
+WHERE did > sqlc.arg('after')
+    AND handle <> ''
+    AND handle !~ '^[a-z0-9.-]*[a-z0-9-]$'
+ORDER BY did
+LIMIT sqlc.arg('limit');
+-- name: RenameEntryHandles :many
+UPDATE entries
+SET handle = v.new_handle
+FROM (
+        SELECT unnest(sqlc.arg('dids')::text []) AS did,
+            unnest(sqlc.arg('old_handles')::text []) AS old_handle,
+            unnest(sqlc.arg('new_handles')::text []) AS new_handle
+    ) AS v
+WHERE entries.did = v.did
+    AND entries.handle = v.old_handle
+RETURNING entries.*;
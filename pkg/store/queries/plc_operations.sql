@@ -0,0 +1,61 @@
+-- name: UpsertPLCOperation :execrows
+INSERT INTO plc_operations (cid, did, handle, operation, nullified, created_at)
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (cid) DO
+UPDATE
+SET nullified = EXCLUDED.nullified
+WHERE plc_operations.cid = EXCLUDED.cid
+    AND plc_operations.nullified <> EXCLUDED.nullified;
+-- name: GetPLCOperationByCID :one
+SELECT *
+FROM plc_operations
+WHERE cid = $1;
+-- name: GetPLCOperationsByDID :many
+SELECT *
+FROM plc_operations
+WHERE did = $1
+ORDER BY created_at;
+-- name: GetHandleClaimsByDID :many
+SELECT *
+FROM plc_operations
+WHERE did = $1
+    AND handle <> ''
+    AND NOT nullified
+ORDER BY created_at;
+-- name: GetHandleClaimsByHandle :many
+SELECT *
+FROM plc_operations
+WHERE handle = $1
+    AND NOT nullified
+ORDER BY created_at;
+-- name: UpsertPLCOperations :many
+INSERT INTO plc_operations (cid, did, handle, operation, nullified, created_at)
+SELECT unnest(sqlc.arg('cids')::text []),
+    unnest(sqlc.arg('dids')::text []),
+    unnest(sqlc.arg('handles')::text []),
+    unnest(sqlc.arg('operations')::text [])::jsonb,
+    unnest(sqlc.arg('nullified')::boolean []),
+    unnest(sqlc.arg('created_ats')::timestamptz []) ON CONFLICT (cid) DO
+UPDATE
+SET nullified = EXCLUDED.nullified
+WHERE plc_operations.cid = EXCLUDED.cid
+    AND plc_operations.nullified <> EXCLUDED.nullified
+RETURNING cid;
+-- name: GetUnnormalizedOperationHandles :many
+SELECT cid,
+    handle
+FROM plc_operations
+WHERE cid > sqlc.arg('after')
+    AND handle <> ''
+    AND handle !~ '^[a-z0-9.-]*[a-z0-9-]$'
+ORDER BY cid
+LIMIT sqlc.arg('limit');
+-- name: RenameOperationHandles :exec
+UPDATE plc_operations
+SET handle = v.new_handle
+FROM (
+        SELECT unnest(sqlc.arg('cids')::text []) AS cid,
+            unnest(sqlc.arg('old_handles')::text []) AS old_handle,
+            unnest(sqlc.arg('new_handles')::text []) AS new_handle
+    ) AS v
+WHERE plc_operations.cid = v.cid
+    AND plc_operations.handle = v.old_handle;
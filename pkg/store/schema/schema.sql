@@ -9,3 +9,58 @@ CREATE TABLE IF NOT EXISTS entries (
     PRIMARY KEY (did)
 );
 CREATE INDEX IF NOT EXISTS entries_handle ON entries (handle);
+ALTER TABLE entries
+ADD COLUMN IF NOT EXISTS validation_status TEXT DEFAULT '' NOT NULL;
+ALTER TABLE entries
+ADD COLUMN IF NOT EXISTS validation_detail TEXT DEFAULT '' NOT NULL;
+ALTER TABLE entries
+ADD COLUMN IF NOT EXISTS next_check_time TIMESTAMPTZ;
+ALTER TABLE entries
+ADD COLUMN IF NOT EXISTS check_streak INTEGER DEFAULT 0 NOT NULL;
+-- Entries last checked before scheduling existed keep their place instead of queueing ahead of new entries,
+-- they're due after the default valid or invalid interval from their last check
+UPDATE entries
+SET next_check_time = last_checked_time + CASE
+        WHEN is_valid THEN INTERVAL '12 hours'
+        ELSE INTERVAL '1 hour'
+    END
+WHERE next_check_time IS NULL
+    AND last_checked_time IS NOT NULL
+    AND validation_status = '';
+CREATE INDEX IF NOT EXISTS entries_next_check_time ON entries (next_check_time NULLS FIRST);
+ALTER TABLE entries
+ADD COLUMN IF NOT EXISTS lease_owner TEXT DEFAULT '' NOT NULL;
+ALTER TABLE entries
+ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+-- PLC Operations
+CREATE TABLE IF NOT EXISTS plc_operations (
+    cid TEXT NOT NULL,
+    did TEXT NOT NULL,
+    handle TEXT DEFAULT '' NOT NULL,
+    operation JSONB NOT NULL,
+    nullified BOOLEAN DEFAULT FALSE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    inserted_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (cid)
+);
+CREATE INDEX IF NOT EXISTS plc_operations_did_created_at ON plc_operations (did, created_at);
+CREATE INDEX IF NOT EXISTS plc_operations_handle ON plc_operations (handle);
+-- DID Documents
+CREATE TABLE IF NOT EXISTS did_documents (
+    did TEXT NOT NULL,
+    also_known_as TEXT [] DEFAULT '{}' NOT NULL,
+    verification_methods JSONB DEFAULT '{}' NOT NULL,
+    rotation_keys TEXT [] DEFAULT '{}' NOT NULL,
+    services JSONB DEFAULT '{}' NOT NULL,
+    chain_invalid BOOLEAN DEFAULT FALSE NOT NULL,
+    chain_error TEXT DEFAULT '' NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (did)
+);
+-- Cursors
+CREATE TABLE IF NOT EXISTS cursors (
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (name)
+);
@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/XSAM/otelsql"
 	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+	"github.com/ericvolp12/bingo/pkg/syntax"
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -29,8 +31,51 @@ type Entry struct {
 	Did             string    `json:"did"`
 	IsValid         bool      `json:"valid"`
 	LastCheckedTime time.Time `json:"checked"`
+
+	// ValidationStatus classifies the outcome of the last validation, ValidationDetail explains it
+	ValidationStatus ValidationStatus `json:"status,omitempty"`
+	ValidationDetail string           `json:"detail,omitempty"`
+
+	// NextCheckTime is when the entry is due to be validated again and CheckStreak is
+	// how many validations in a row have had the same outcome, they're only kept in postgres
+	NextCheckTime time.Time `json:"-"`
+	CheckStreak   int       `json:"-"`
 }
 
+// ValidationStatus classifies the outcome of validating a handle
+type ValidationStatus string
+
+const (
+	// ValidationStatusUnchecked is an entry that hasn't been validated since its handle was set
+	ValidationStatusUnchecked ValidationStatus = ""
+	ValidationStatusValid     ValidationStatus = "valid"
+	// ValidationStatusUnclaimed means the DID document doesn't claim the handle
+	ValidationStatusUnclaimed ValidationStatus = "unclaimed"
+	// ValidationStatusDocumentError means the DID document couldn't be loaded.
+	// It's transient, the entry keeps the validity of its last real outcome.
+	ValidationStatusDocumentError ValidationStatus = "document_error"
+	// ValidationStatusNXDomain means the handle's domain doesn't exist
+	ValidationStatusNXDomain ValidationStatus = "nxdomain"
+	// ValidationStatusNoRecord means the name looked up exists but has no _atproto TXT records
+	ValidationStatusNoRecord ValidationStatus = "no_record"
+	// ValidationStatusTXTMismatch means _atproto TXT records exist but none point at the DID
+	ValidationStatusTXTMismatch ValidationStatus = "txt_mismatch"
+	// ValidationStatusDNSError means the TXT lookup failed for a reason other than the name not existing
+	ValidationStatusDNSError ValidationStatus = "dns_error"
+	// ValidationStatusHTTPStatus means /.well-known/atproto-did responded with a non-200 status
+	ValidationStatusHTTPStatus ValidationStatus = "http_status"
+	// ValidationStatusTLSError means the handle's HTTPS certificate or handshake was invalid
+	ValidationStatusTLSError ValidationStatus = "tls_error"
+	// ValidationStatusTimeout means the HTTPS request timed out
+	ValidationStatusTimeout ValidationStatus = "timeout"
+	// ValidationStatusConnectionError means the HTTPS request failed to connect for another reason
+	ValidationStatusConnectionError ValidationStatus = "connection_error"
+	// ValidationStatusBodyMismatch means /.well-known/atproto-did was served but didn't contain the DID
+	ValidationStatusBodyMismatch ValidationStatus = "body_mismatch"
+	// ValidationStatusBodyTooLarge means /.well-known/atproto-did was larger than the fetcher accepts
+	ValidationStatusBodyTooLarge ValidationStatus = "body_too_large"
+)
+
 var ErrNotFound = errors.New("bingo: not found")
 
 var tracer = otel.Tracer("bingo/store")
@@ -103,11 +148,18 @@ func NewStore(
 		pipeline := client.Pipeline()
 
 		for _, dbEntry := range dbEntries {
-			entry := &Entry{
-				Handle:          dbEntry.Handle,
-				Did:             dbEntry.Did,
-				IsValid:         dbEntry.IsValid,
-				LastCheckedTime: dbEntry.LastCheckedTime.Time,
+			entry := entryFromDB(dbEntry)
+
+			// Lookups normalize the handle first, so rows from before handles were normalized are keyed
+			// the same way and those that can't be normalized are only reachable by DID
+			handle := ""
+			if entry.Handle != "" {
+				handle, err = syntax.NormalizeHandle(entry.Handle)
+				if err != nil {
+					log.Printf("skipping handle key for %s: %+v", entry.Did, err)
+				} else {
+					entry.Handle = handle
+				}
 			}
 
 			byDidKey := fmt.Sprintf("%s_%s_%s", prefix, byDidPrefix, entry.Did)
@@ -119,7 +171,9 @@ func NewStore(
 			}
 
 			pipeline.Set(ctx, byDidKey, val, 0)
-			pipeline.Set(ctx, byHandleKey, val, 0)
+			if handle != "" {
+				pipeline.Set(ctx, byHandleKey, val, 0)
+			}
 		}
 
 		_, err = pipeline.Exec(ctx)
@@ -143,8 +197,12 @@ func (s *Store) Lookup(ctx context.Context, handleOrDid string) (*Entry, error)
 	defer span.End()
 	span.SetAttributes(attribute.String("handleOrDid", handleOrDid))
 
+	handleOrDid, err := syntax.NormalizeIdentifier(handleOrDid)
+	if err != nil {
+		return nil, fmt.Errorf("bingo: %w", err)
+	}
+
 	var val string
-	var err error
 
 	if IsDID(handleOrDid) {
 		key := fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byDidPrefix, handleOrDid)
@@ -169,6 +227,37 @@ func (s *Store) Lookup(ctx context.Context, handleOrDid string) (*Entry, error)
 	return entry, nil
 }
 
+// GetEntry returns an entry from postgres, including its validation schedule
+func (s *Store) GetEntry(ctx context.Context, did string) (*Entry, error) {
+	ctx, span := tracer.Start(ctx, "GetEntry")
+	defer span.End()
+	span.SetAttributes(attribute.String("did", did))
+
+	dbEntry, err := s.Queries.GetEntryByDID(ctx, did)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("bingo: failed to get entry: %w", err)
+	}
+
+	return entryFromDB(dbEntry), nil
+}
+
+// entryFromDB converts a postgres row into an Entry
+func entryFromDB(dbEntry store_queries.Entry) *Entry {
+	return &Entry{
+		Handle:           dbEntry.Handle,
+		Did:              dbEntry.Did,
+		IsValid:          dbEntry.IsValid,
+		LastCheckedTime:  dbEntry.LastCheckedTime.Time,
+		ValidationStatus: ValidationStatus(dbEntry.ValidationStatus),
+		ValidationDetail: dbEntry.ValidationDetail,
+		NextCheckTime:    dbEntry.NextCheckTime.Time,
+		CheckStreak:      int(dbEntry.CheckStreak),
+	}
+}
+
 func (s *Store) BulkLookupByDid(ctx context.Context, dids []string) ([]*Entry, error) {
 	ctx, span := tracer.Start(ctx, "BulkLookupByDid")
 	defer span.End()
@@ -312,53 +401,141 @@ func (s *Store) Update(ctx context.Context, entry *Entry) error {
 	return nil
 }
 
-func (s *Store) BulkUpdateEntryValidation(ctx context.Context, entries []*Entry) error {
-	ctx, span := tracer.Start(ctx, "BulkUpdateEntries")
+// BulkUpdate upserts the handles of many entries at once, replacing stale handle keys in redis
+func (s *Store) BulkUpdate(ctx context.Context, entries []*Entry) error {
+	ctx, span := tracer.Start(ctx, "BulkUpdate")
 	defer span.End()
+	span.SetAttributes(attribute.Int("entries", len(entries)))
 
-	// Split valid and invalid entries
-	validDids := []string{}
-	invalidDids := []string{}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := updateEntries(ctx, s.Queries, entries); err != nil {
+		return err
+	}
 
+	return s.cacheEntries(ctx, entries)
+}
+
+func updateEntries(ctx context.Context, queries *store_queries.Queries, entries []*Entry) error {
+	dids := make([]string, 0, len(entries))
+	handles := make([]string, 0, len(entries))
 	for _, entry := range entries {
-		if entry.IsValid {
-			validDids = append(validDids, entry.Did)
-		} else {
-			invalidDids = append(invalidDids, entry.Did)
-		}
+		dids = append(dids, entry.Did)
+		handles = append(handles, entry.Handle)
 	}
 
-	lastCheckedSQLTime := sql.NullTime{
-		Time:  time.Now(),
-		Valid: true,
+	err := queries.UpdateEntries(ctx, store_queries.UpdateEntriesParams{
+		Dids:    dids,
+		Handles: handles,
+	})
+	if err != nil {
+		return fmt.Errorf("bingo: failed to update entries: %w", err)
 	}
 
-	// Update entries in two batches, one for valid entries and one for invalid entries
-	if len(validDids) > 0 {
-		err := s.Queries.UpdateEntriesValidation(ctx, store_queries.UpdateEntriesValidationParams{
-			LastCheckedTime: lastCheckedSQLTime,
-			IsValid:         true,
-			Dids:            validDids,
-		})
-		if err != nil {
-			return fmt.Errorf("bingo: failed to update entries: %w", err)
-		}
+	return nil
+}
+
+// cacheEntries writes entries to redis, replacing stale handle keys
+func (s *Store) cacheEntries(ctx context.Context, entries []*Entry) error {
+	// Lookup the old entries by did
+	pipeline := s.Redis.Pipeline()
+	for _, entry := range entries {
+		pipeline.Get(ctx, fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byDidPrefix, entry.Did))
 	}
-	if len(invalidDids) > 0 {
-		err := s.Queries.UpdateEntriesValidation(ctx, store_queries.UpdateEntriesValidationParams{
-			LastCheckedTime: lastCheckedSQLTime,
-			IsValid:         false,
-			Dids:            invalidDids,
-		})
+
+	results, err := pipeline.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("bingo: failed to lookup entries by did: %w", err)
+	}
+
+	pipeline = s.Redis.Pipeline()
+	for i, entry := range entries {
+		byDidVal, err := results[i].(*redis.StringCmd).Result()
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("bingo: failed to lookup entry by did: %w", err)
+		}
+
+		if byDidVal != "" {
+			oldDidEntry := &Entry{}
+			if err := json.Unmarshal([]byte(byDidVal), oldDidEntry); err != nil {
+				return fmt.Errorf("bingo: failed to unmarshal old entry: %w", err)
+			}
+
+			// If the old entry's handle is different from the new entry's handle, delete the old entry by handle
+			if entry.Handle != oldDidEntry.Handle && entry.Did == oldDidEntry.Did {
+				pipeline.Del(ctx, fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byHandlePrefix, oldDidEntry.Handle))
+			}
+		}
+
+		val, err := json.Marshal(entry)
 		if err != nil {
-			return fmt.Errorf("bingo: failed to update entries: %w", err)
+			return fmt.Errorf("bingo: failed to marshal entry: %w", err)
 		}
+
+		pipeline.Set(ctx, fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byDidPrefix, entry.Did), val, 0)
+		pipeline.Set(ctx, fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byHandlePrefix, entry.Handle), val, 0)
+	}
+
+	_, err = pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("bingo: failed to execute pipeline: %w", err)
+	}
+
+	return nil
+}
+
+// BulkUpdateEntryValidation records the outcome of validating entries. Entries whose handle changed since
+// they were validated are skipped. With an owner, only entries whose validation lease the owner still holds
+// are written and their leases are released. With no owner the entries are written whoever holds their
+// leases, and the leases are left alone.
+func (s *Store) BulkUpdateEntryValidation(ctx context.Context, owner string, entries []*Entry) error {
+	ctx, span := tracer.Start(ctx, "BulkUpdateEntries")
+	defer span.End()
+
+	params := store_queries.UpdateEntriesValidationParams{
+		LastCheckedTime: sql.NullTime{
+			Time:  time.Now(),
+			Valid: true,
+		},
+		LeaseOwner: owner,
+	}
+	for _, entry := range entries {
+		params.Dids = append(params.Dids, entry.Did)
+		params.Handles = append(params.Handles, entry.Handle)
+		params.IsValids = append(params.IsValids, entry.IsValid)
+		params.Statuses = append(params.Statuses, string(entry.ValidationStatus))
+		params.Details = append(params.Details, entry.ValidationDetail)
+		params.NextCheckTimes = append(params.NextCheckTimes, entry.NextCheckTime)
+		params.CheckStreaks = append(params.CheckStreaks, int32(entry.CheckStreak))
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	updated, err := s.Queries.UpdateEntriesValidation(ctx, params)
+	if err != nil {
+		return fmt.Errorf("bingo: failed to update entries: %w", err)
+	}
+	span.SetAttributes(attribute.Int("entries", len(entries)), attribute.Int("updated", len(updated)))
+
+	// Entries whose lease was lost to another replica are left to its result, and those whose handle
+	// changed are left to the next validation, so a stale result never reaches the cache
+	written := make(map[string]string, len(updated))
+	for _, row := range updated {
+		written[row.Did] = row.Handle
 	}
 
 	// Set the entries in redis
 	pipeline := s.Redis.Pipeline()
 
 	for _, entry := range entries {
+		if handle, ok := written[entry.Did]; !ok || handle != entry.Handle {
+			continue
+		}
+
 		byDidKey := fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byDidPrefix, entry.Did)
 		byHandleKey := fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byHandlePrefix, entry.Handle)
 
@@ -372,7 +549,7 @@ func (s *Store) BulkUpdateEntryValidation(ctx context.Context, entries []*Entry)
 		pipeline.Set(ctx, byHandleKey, val, 0)
 	}
 
-	_, err := pipeline.Exec(ctx)
+	_, err = pipeline.Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("bingo: failed to execute pipeline: %w", err)
 	}
@@ -380,11 +557,79 @@ func (s *Store) BulkUpdateEntryValidation(ctx context.Context, entries []*Entry)
 	return nil
 }
 
+// RenewEntryLeases extends the validation leases an owner still holds on entries by ttl
+func (s *Store) RenewEntryLeases(ctx context.Context, owner string, dids []string, ttl time.Duration) error {
+	ctx, span := tracer.Start(ctx, "RenewEntryLeases")
+	defer span.End()
+	span.SetAttributes(attribute.Int("entries", len(dids)))
+
+	if len(dids) == 0 {
+		return nil
+	}
+
+	err := s.Queries.RenewEntryLeases(ctx, store_queries.RenewEntryLeasesParams{
+		LeaseSeconds: ttl.Seconds(),
+		Dids:         dids,
+		LeaseOwner:   owner,
+	})
+	if err != nil {
+		return fmt.Errorf("bingo: failed to renew entry leases: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseEntryLeases gives up the validation leases an owner holds on entries it won't validate,
+// so they can be claimed again without waiting for the leases to expire
+func (s *Store) ReleaseEntryLeases(ctx context.Context, owner string, dids []string) error {
+	ctx, span := tracer.Start(ctx, "ReleaseEntryLeases")
+	defer span.End()
+	span.SetAttributes(attribute.Int("entries", len(dids)))
+
+	if len(dids) == 0 {
+		return nil
+	}
+
+	err := s.Queries.ReleaseEntryLeases(ctx, store_queries.ReleaseEntryLeasesParams{
+		Dids:       dids,
+		LeaseOwner: owner,
+	})
+	if err != nil {
+		return fmt.Errorf("bingo: failed to release entry leases: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Store) Delete(ctx context.Context, did string) error {
 	ctx, span := tracer.Start(ctx, "Delete")
 	defer span.End()
 	span.SetAttributes(attribute.String("did", did))
 
+	if err := deleteEntry(ctx, s.Queries, did); err != nil {
+		return err
+	}
+
+	return s.uncacheEntry(ctx, did)
+}
+
+// deleteEntry deletes an entry and its DID document in postgres
+func deleteEntry(ctx context.Context, queries *store_queries.Queries, did string) error {
+	err := queries.DeleteEntry(ctx, did)
+	if err != nil {
+		return fmt.Errorf("bingo: failed to delete entry: %w", err)
+	}
+
+	err = queries.DeleteDIDDocument(ctx, did)
+	if err != nil {
+		return fmt.Errorf("bingo: failed to delete DID document: %w", err)
+	}
+
+	return nil
+}
+
+// uncacheEntry deletes an entry from redis by did and by handle
+func (s *Store) uncacheEntry(ctx context.Context, did string) error {
 	// Lookup the old entry by did
 	byDidKey := fmt.Sprintf("%s_%s_%s", s.RedisPrefix, byDidPrefix, did)
 	byDidVal, err := s.Redis.Get(ctx, byDidKey).Result()
@@ -0,0 +1,41 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: cursors.sql
+
+package store_queries
+
+import (
+	"context"
+)
+
+const getCursor = `-- name: GetCursor :one
+SELECT value
+FROM cursors
+WHERE name = $1
+`
+
+func (q *Queries) GetCursor(ctx context.Context, name string) (string, error) {
+	row := q.queryRow(ctx, q.getCursorStmt, getCursor, name)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const setCursor = `-- name: SetCursor :exec
+INSERT INTO cursors (name, value, updated_at)
+VALUES ($1, $2, NOW()) ON CONFLICT (name) DO
+UPDATE
+SET value = EXCLUDED.value,
+    updated_at = EXCLUDED.updated_at
+`
+
+type SetCursorParams struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (q *Queries) SetCursor(ctx context.Context, arg SetCursorParams) error {
+	_, err := q.exec(ctx, q.setCursorStmt, setCursor, arg.Name, arg.Value)
+	return err
+}
@@ -24,39 +24,127 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.claimEntriesForValidationStmt, err = db.PrepareContext(ctx, claimEntriesForValidation); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimEntriesForValidation: %w", err)
+	}
+	if q.deleteDIDDocumentStmt, err = db.PrepareContext(ctx, deleteDIDDocument); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDIDDocument: %w", err)
+	}
+	if q.deleteEntryStmt, err = db.PrepareContext(ctx, deleteEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEntry: %w", err)
+	}
+	if q.flagDIDChainInvalidStmt, err = db.PrepareContext(ctx, flagDIDChainInvalid); err != nil {
+		return nil, fmt.Errorf("error preparing query FlagDIDChainInvalid: %w", err)
+	}
+	if q.getCursorStmt, err = db.PrepareContext(ctx, getCursor); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCursor: %w", err)
+	}
+	if q.getDIDDocumentStmt, err = db.PrepareContext(ctx, getDIDDocument); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDIDDocument: %w", err)
+	}
 	if q.getEntriesStmt, err = db.PrepareContext(ctx, getEntries); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEntries: %w", err)
 	}
-	if q.getEntriesForValidationStmt, err = db.PrepareContext(ctx, getEntriesForValidation); err != nil {
-		return nil, fmt.Errorf("error preparing query GetEntriesForValidation: %w", err)
-	}
 	if q.getEntryByDIDStmt, err = db.PrepareContext(ctx, getEntryByDID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEntryByDID: %w", err)
 	}
 	if q.getEntryByHandleStmt, err = db.PrepareContext(ctx, getEntryByHandle); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEntryByHandle: %w", err)
 	}
+	if q.getHandleClaimsByDIDStmt, err = db.PrepareContext(ctx, getHandleClaimsByDID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetHandleClaimsByDID: %w", err)
+	}
+	if q.getHandleClaimsByHandleStmt, err = db.PrepareContext(ctx, getHandleClaimsByHandle); err != nil {
+		return nil, fmt.Errorf("error preparing query GetHandleClaimsByHandle: %w", err)
+	}
+	if q.getPLCOperationByCIDStmt, err = db.PrepareContext(ctx, getPLCOperationByCID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPLCOperationByCID: %w", err)
+	}
+	if q.getPLCOperationsByDIDStmt, err = db.PrepareContext(ctx, getPLCOperationsByDID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPLCOperationsByDID: %w", err)
+	}
+	if q.getUnnormalizedEntryHandlesStmt, err = db.PrepareContext(ctx, getUnnormalizedEntryHandles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUnnormalizedEntryHandles: %w", err)
+	}
+	if q.getUnnormalizedOperationHandlesStmt, err = db.PrepareContext(ctx, getUnnormalizedOperationHandles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUnnormalizedOperationHandles: %w", err)
+	}
+	if q.releaseEntryLeasesStmt, err = db.PrepareContext(ctx, releaseEntryLeases); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseEntryLeases: %w", err)
+	}
+	if q.renameEntryHandlesStmt, err = db.PrepareContext(ctx, renameEntryHandles); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameEntryHandles: %w", err)
+	}
+	if q.renameOperationHandlesStmt, err = db.PrepareContext(ctx, renameOperationHandles); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameOperationHandles: %w", err)
+	}
+	if q.renewEntryLeasesStmt, err = db.PrepareContext(ctx, renewEntryLeases); err != nil {
+		return nil, fmt.Errorf("error preparing query RenewEntryLeases: %w", err)
+	}
+	if q.setCursorStmt, err = db.PrepareContext(ctx, setCursor); err != nil {
+		return nil, fmt.Errorf("error preparing query SetCursor: %w", err)
+	}
+	if q.updateEntriesStmt, err = db.PrepareContext(ctx, updateEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateEntries: %w", err)
+	}
 	if q.updateEntriesValidationStmt, err = db.PrepareContext(ctx, updateEntriesValidation); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateEntriesValidation: %w", err)
 	}
 	if q.updateEntryStmt, err = db.PrepareContext(ctx, updateEntry); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateEntry: %w", err)
 	}
+	if q.upsertDIDDocumentStmt, err = db.PrepareContext(ctx, upsertDIDDocument); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertDIDDocument: %w", err)
+	}
+	if q.upsertDIDDocumentsStmt, err = db.PrepareContext(ctx, upsertDIDDocuments); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertDIDDocuments: %w", err)
+	}
+	if q.upsertPLCOperationStmt, err = db.PrepareContext(ctx, upsertPLCOperation); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertPLCOperation: %w", err)
+	}
+	if q.upsertPLCOperationsStmt, err = db.PrepareContext(ctx, upsertPLCOperations); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertPLCOperations: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.claimEntriesForValidationStmt != nil {
+		if cerr := q.claimEntriesForValidationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimEntriesForValidationStmt: %w", cerr)
+		}
+	}
+	if q.deleteDIDDocumentStmt != nil {
+		if cerr := q.deleteDIDDocumentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDIDDocumentStmt: %w", cerr)
+		}
+	}
+	if q.deleteEntryStmt != nil {
+		if cerr := q.deleteEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEntryStmt: %w", cerr)
+		}
+	}
+	if q.flagDIDChainInvalidStmt != nil {
+		if cerr := q.flagDIDChainInvalidStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing flagDIDChainInvalidStmt: %w", cerr)
+		}
+	}
+	if q.getCursorStmt != nil {
+		if cerr := q.getCursorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCursorStmt: %w", cerr)
+		}
+	}
+	if q.getDIDDocumentStmt != nil {
+		if cerr := q.getDIDDocumentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDIDDocumentStmt: %w", cerr)
+		}
+	}
 	if q.getEntriesStmt != nil {
 		if cerr := q.getEntriesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getEntriesStmt: %w", cerr)
 		}
 	}
-	if q.getEntriesForValidationStmt != nil {
-		if cerr := q.getEntriesForValidationStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getEntriesForValidationStmt: %w", cerr)
-		}
-	}
 	if q.getEntryByDIDStmt != nil {
 		if cerr := q.getEntryByDIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getEntryByDIDStmt: %w", cerr)
@@ -67,6 +155,66 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getEntryByHandleStmt: %w", cerr)
 		}
 	}
+	if q.getHandleClaimsByDIDStmt != nil {
+		if cerr := q.getHandleClaimsByDIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getHandleClaimsByDIDStmt: %w", cerr)
+		}
+	}
+	if q.getHandleClaimsByHandleStmt != nil {
+		if cerr := q.getHandleClaimsByHandleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getHandleClaimsByHandleStmt: %w", cerr)
+		}
+	}
+	if q.getPLCOperationByCIDStmt != nil {
+		if cerr := q.getPLCOperationByCIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPLCOperationByCIDStmt: %w", cerr)
+		}
+	}
+	if q.getPLCOperationsByDIDStmt != nil {
+		if cerr := q.getPLCOperationsByDIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPLCOperationsByDIDStmt: %w", cerr)
+		}
+	}
+	if q.getUnnormalizedEntryHandlesStmt != nil {
+		if cerr := q.getUnnormalizedEntryHandlesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUnnormalizedEntryHandlesStmt: %w", cerr)
+		}
+	}
+	if q.getUnnormalizedOperationHandlesStmt != nil {
+		if cerr := q.getUnnormalizedOperationHandlesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUnnormalizedOperationHandlesStmt: %w", cerr)
+		}
+	}
+	if q.releaseEntryLeasesStmt != nil {
+		if cerr := q.releaseEntryLeasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseEntryLeasesStmt: %w", cerr)
+		}
+	}
+	if q.renameEntryHandlesStmt != nil {
+		if cerr := q.renameEntryHandlesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameEntryHandlesStmt: %w", cerr)
+		}
+	}
+	if q.renameOperationHandlesStmt != nil {
+		if cerr := q.renameOperationHandlesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameOperationHandlesStmt: %w", cerr)
+		}
+	}
+	if q.renewEntryLeasesStmt != nil {
+		if cerr := q.renewEntryLeasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renewEntryLeasesStmt: %w", cerr)
+		}
+	}
+	if q.setCursorStmt != nil {
+		if cerr := q.setCursorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setCursorStmt: %w", cerr)
+		}
+	}
+	if q.updateEntriesStmt != nil {
+		if cerr := q.updateEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateEntriesStmt: %w", cerr)
+		}
+	}
 	if q.updateEntriesValidationStmt != nil {
 		if cerr := q.updateEntriesValidationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateEntriesValidationStmt: %w", cerr)
@@ -77,6 +225,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateEntryStmt: %w", cerr)
 		}
 	}
+	if q.upsertDIDDocumentStmt != nil {
+		if cerr := q.upsertDIDDocumentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertDIDDocumentStmt: %w", cerr)
+		}
+	}
+	if q.upsertDIDDocumentsStmt != nil {
+		if cerr := q.upsertDIDDocumentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertDIDDocumentsStmt: %w", cerr)
+		}
+	}
+	if q.upsertPLCOperationStmt != nil {
+		if cerr := q.upsertPLCOperationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertPLCOperationStmt: %w", cerr)
+		}
+	}
+	if q.upsertPLCOperationsStmt != nil {
+		if cerr := q.upsertPLCOperationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertPLCOperationsStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -114,25 +282,67 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                          DBTX
-	tx                          *sql.Tx
-	getEntriesStmt              *sql.Stmt
-	getEntriesForValidationStmt *sql.Stmt
-	getEntryByDIDStmt           *sql.Stmt
-	getEntryByHandleStmt        *sql.Stmt
-	updateEntriesValidationStmt *sql.Stmt
-	updateEntryStmt             *sql.Stmt
+	db                                  DBTX
+	tx                                  *sql.Tx
+	claimEntriesForValidationStmt       *sql.Stmt
+	deleteDIDDocumentStmt               *sql.Stmt
+	deleteEntryStmt                     *sql.Stmt
+	flagDIDChainInvalidStmt             *sql.Stmt
+	getCursorStmt                       *sql.Stmt
+	getDIDDocumentStmt                  *sql.Stmt
+	getEntriesStmt                      *sql.Stmt
+	getEntryByDIDStmt                   *sql.Stmt
+	getEntryByHandleStmt                *sql.Stmt
+	getHandleClaimsByDIDStmt            *sql.Stmt
+	getHandleClaimsByHandleStmt         *sql.Stmt
+	getPLCOperationByCIDStmt            *sql.Stmt
+	getPLCOperationsByDIDStmt           *sql.Stmt
+	getUnnormalizedEntryHandlesStmt     *sql.Stmt
+	getUnnormalizedOperationHandlesStmt *sql.Stmt
+	releaseEntryLeasesStmt              *sql.Stmt
+	renameEntryHandlesStmt              *sql.Stmt
+	renameOperationHandlesStmt          *sql.Stmt
+	renewEntryLeasesStmt                *sql.Stmt
+	setCursorStmt                       *sql.Stmt
+	updateEntriesStmt                   *sql.Stmt
+	updateEntriesValidationStmt         *sql.Stmt
+	updateEntryStmt                     *sql.Stmt
+	upsertDIDDocumentStmt               *sql.Stmt
+	upsertDIDDocumentsStmt              *sql.Stmt
+	upsertPLCOperationStmt              *sql.Stmt
+	upsertPLCOperationsStmt             *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                          tx,
-		tx:                          tx,
-		getEntriesStmt:              q.getEntriesStmt,
-		getEntriesForValidationStmt: q.getEntriesForValidationStmt,
-		getEntryByDIDStmt:           q.getEntryByDIDStmt,
-		getEntryByHandleStmt:        q.getEntryByHandleStmt,
-		updateEntriesValidationStmt: q.updateEntriesValidationStmt,
-		updateEntryStmt:             q.updateEntryStmt,
+		db:                                  tx,
+		tx:                                  tx,
+		claimEntriesForValidationStmt:       q.claimEntriesForValidationStmt,
+		deleteDIDDocumentStmt:               q.deleteDIDDocumentStmt,
+		deleteEntryStmt:                     q.deleteEntryStmt,
+		flagDIDChainInvalidStmt:             q.flagDIDChainInvalidStmt,
+		getCursorStmt:                       q.getCursorStmt,
+		getDIDDocumentStmt:                  q.getDIDDocumentStmt,
+		getEntriesStmt:                      q.getEntriesStmt,
+		getEntryByDIDStmt:                   q.getEntryByDIDStmt,
+		getEntryByHandleStmt:                q.getEntryByHandleStmt,
+		getHandleClaimsByDIDStmt:            q.getHandleClaimsByDIDStmt,
+		getHandleClaimsByHandleStmt:         q.getHandleClaimsByHandleStmt,
+		getPLCOperationByCIDStmt:            q.getPLCOperationByCIDStmt,
+		getPLCOperationsByDIDStmt:           q.getPLCOperationsByDIDStmt,
+		getUnnormalizedEntryHandlesStmt:     q.getUnnormalizedEntryHandlesStmt,
+		getUnnormalizedOperationHandlesStmt: q.getUnnormalizedOperationHandlesStmt,
+		releaseEntryLeasesStmt:              q.releaseEntryLeasesStmt,
+		renameEntryHandlesStmt:              q.renameEntryHandlesStmt,
+		renameOperationHandlesStmt:          q.renameOperationHandlesStmt,
+		renewEntryLeasesStmt:                q.renewEntryLeasesStmt,
+		setCursorStmt:                       q.setCursorStmt,
+		updateEntriesStmt:                   q.updateEntriesStmt,
+		updateEntriesValidationStmt:         q.updateEntriesValidationStmt,
+		updateEntryStmt:                     q.updateEntryStmt,
+		upsertDIDDocumentStmt:               q.upsertDIDDocumentStmt,
+		upsertDIDDocumentsStmt:              q.upsertDIDDocumentsStmt,
+		upsertPLCOperationStmt:              q.upsertPLCOperationStmt,
+		upsertPLCOperationsStmt:             q.upsertPLCOperationsStmt,
 	}
 }
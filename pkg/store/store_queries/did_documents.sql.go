@@ -0,0 +1,156 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: did_documents.sql
+
+package store_queries
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/lib/pq"
+)
+
+const deleteDIDDocument = `-- name: DeleteDIDDocument :exec
+DELETE FROM did_documents
+WHERE did = $1
+`
+
+func (q *Queries) DeleteDIDDocument(ctx context.Context, did string) error {
+	_, err := q.exec(ctx, q.deleteDIDDocumentStmt, deleteDIDDocument, did)
+	return err
+}
+
+const flagDIDChainInvalid = `-- name: FlagDIDChainInvalid :exec
+INSERT INTO did_documents (did, chain_invalid, chain_error)
+VALUES ($1, TRUE, $2) ON CONFLICT (did) DO
+UPDATE
+SET chain_invalid = TRUE,
+    chain_error = EXCLUDED.chain_error
+WHERE did_documents.did = EXCLUDED.did
+`
+
+type FlagDIDChainInvalidParams struct {
+	Did        string `json:"did"`
+	ChainError string `json:"chain_error"`
+}
+
+func (q *Queries) FlagDIDChainInvalid(ctx context.Context, arg FlagDIDChainInvalidParams) error {
+	_, err := q.exec(ctx, q.flagDIDChainInvalidStmt, flagDIDChainInvalid, arg.Did, arg.ChainError)
+	return err
+}
+
+const getDIDDocument = `-- name: GetDIDDocument :one
+SELECT did, also_known_as, verification_methods, rotation_keys, services, chain_invalid, chain_error, updated_at
+FROM did_documents
+WHERE did = $1
+`
+
+func (q *Queries) GetDIDDocument(ctx context.Context, did string) (DidDocument, error) {
+	row := q.queryRow(ctx, q.getDIDDocumentStmt, getDIDDocument, did)
+	var i DidDocument
+	err := row.Scan(
+		&i.Did,
+		pq.Array(&i.AlsoKnownAs),
+		&i.VerificationMethods,
+		pq.Array(&i.RotationKeys),
+		&i.Services,
+		&i.ChainInvalid,
+		&i.ChainError,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertDIDDocument = `-- name: UpsertDIDDocument :exec
+INSERT INTO did_documents (
+        did,
+        also_known_as,
+        verification_methods,
+        rotation_keys,
+        services,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4, $5, NOW()) ON CONFLICT (did) DO
+UPDATE
+SET also_known_as = EXCLUDED.also_known_as,
+    verification_methods = EXCLUDED.verification_methods,
+    rotation_keys = EXCLUDED.rotation_keys,
+    services = EXCLUDED.services,
+    updated_at = EXCLUDED.updated_at
+WHERE did_documents.did = EXCLUDED.did
+`
+
+type UpsertDIDDocumentParams struct {
+	Did                 string          `json:"did"`
+	AlsoKnownAs         []string        `json:"also_known_as"`
+	VerificationMethods json.RawMessage `json:"verification_methods"`
+	RotationKeys        []string        `json:"rotation_keys"`
+	Services            json.RawMessage `json:"services"`
+}
+
+func (q *Queries) UpsertDIDDocument(ctx context.Context, arg UpsertDIDDocumentParams) error {
+	_, err := q.exec(ctx, q.upsertDIDDocumentStmt, upsertDIDDocument,
+		arg.Did,
+		pq.Array(arg.AlsoKnownAs),
+		arg.VerificationMethods,
+		pq.Array(arg.RotationKeys),
+		arg.Services,
+	)
+	return err
+}
+
+const upsertDIDDocuments = `-- name: UpsertDIDDocuments :exec
+INSERT INTO did_documents (
+        did,
+        also_known_as,
+        verification_methods,
+        rotation_keys,
+        services,
+        updated_at
+    )
+SELECT docs.did,
+    ARRAY(
+        SELECT jsonb_array_elements_text(docs.also_known_as::jsonb)
+    ),
+    docs.verification_methods::jsonb,
+    ARRAY(
+        SELECT jsonb_array_elements_text(docs.rotation_keys::jsonb)
+    ),
+    docs.services::jsonb,
+    NOW()
+FROM (
+        SELECT unnest($1::text []) AS did,
+            unnest($2::text []) AS also_known_as,
+            unnest($3::text []) AS verification_methods,
+            unnest($4::text []) AS rotation_keys,
+            unnest($5::text []) AS services
+    ) AS docs ON CONFLICT (did) DO
+UPDATE
+SET also_known_as = EXCLUDED.also_known_as,
+    verification_methods = EXCLUDED.verification_methods,
+    rotation_keys = EXCLUDED.rotation_keys,
+    services = EXCLUDED.services,
+    updated_at = EXCLUDED.updated_at
+WHERE did_documents.did = EXCLUDED.did
+`
+
+type UpsertDIDDocumentsParams struct {
+	Dids                []string `json:"dids"`
+	AlsoKnownAs         []string `json:"also_known_as"`
+	VerificationMethods []string `json:"verification_methods"`
+	RotationKeys        []string `json:"rotation_keys"`
+	Services            []string `json:"services"`
+}
+
+func (q *Queries) UpsertDIDDocuments(ctx context.Context, arg UpsertDIDDocumentsParams) error {
+	_, err := q.exec(ctx, q.upsertDIDDocumentsStmt, upsertDIDDocuments,
+		pq.Array(arg.Dids),
+		pq.Array(arg.AlsoKnownAs),
+		pq.Array(arg.VerificationMethods),
+		pq.Array(arg.RotationKeys),
+		pq.Array(arg.Services),
+	)
+	return err
+}
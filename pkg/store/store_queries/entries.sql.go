@@ -8,24 +8,41 @@ package store_queries
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/lib/pq"
 )
 
-const getEntries = `-- name: GetEntries :many
-SELECT did, handle, is_valid, last_checked_time, created_at, updated_at
-FROM entries
-ORDER BY did
-LIMIT $1 OFFSET $2
+const claimEntriesForValidation = `-- name: ClaimEntriesForValidation :many
+UPDATE entries
+SET lease_owner = $1,
+    lease_expires_at = NOW() + $2::float8 * INTERVAL '1 second'
+WHERE did IN (
+        SELECT did
+        FROM entries
+        WHERE (
+                next_check_time IS NULL
+                OR next_check_time <= NOW()
+            )
+            AND (
+                lease_expires_at IS NULL
+                OR lease_expires_at <= NOW()
+            )
+        ORDER BY next_check_time NULLS FIRST
+        LIMIT $3
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING did, handle, is_valid, last_checked_time, created_at, updated_at, validation_status, validation_detail, next_check_time, check_streak, lease_owner, lease_expires_at
 `
 
-type GetEntriesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type ClaimEntriesForValidationParams struct {
+	LeaseOwner   string  `json:"lease_owner"`
+	LeaseSeconds float64 `json:"lease_seconds"`
+	Limit        int32   `json:"limit"`
 }
 
-func (q *Queries) GetEntries(ctx context.Context, arg GetEntriesParams) ([]Entry, error) {
-	rows, err := q.query(ctx, q.getEntriesStmt, getEntries, arg.Limit, arg.Offset)
+func (q *Queries) ClaimEntriesForValidation(ctx context.Context, arg ClaimEntriesForValidationParams) ([]Entry, error) {
+	rows, err := q.query(ctx, q.claimEntriesForValidationStmt, claimEntriesForValidation, arg.LeaseOwner, arg.LeaseSeconds, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -40,6 +57,12 @@ func (q *Queries) GetEntries(ctx context.Context, arg GetEntriesParams) ([]Entry
 			&i.LastCheckedTime,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ValidationStatus,
+			&i.ValidationDetail,
+			&i.NextCheckTime,
+			&i.CheckStreak,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -54,22 +77,30 @@ func (q *Queries) GetEntries(ctx context.Context, arg GetEntriesParams) ([]Entry
 	return items, nil
 }
 
-const getEntriesForValidation = `-- name: GetEntriesForValidation :many
-SELECT did, handle, is_valid, last_checked_time, created_at, updated_at
-from entries
-WHERE last_checked_time is NULL
-    OR last_checked_time < $1
+const deleteEntry = `-- name: DeleteEntry :exec
+DELETE FROM entries
+WHERE did = $1
+`
+
+func (q *Queries) DeleteEntry(ctx context.Context, did string) error {
+	_, err := q.exec(ctx, q.deleteEntryStmt, deleteEntry, did)
+	return err
+}
+
+const getEntries = `-- name: GetEntries :many
+SELECT did, handle, is_valid, last_checked_time, created_at, updated_at, validation_status, validation_detail, next_check_time, check_streak, lease_owner, lease_expires_at
+FROM entries
 ORDER BY did
-LIMIT $2
+LIMIT $1 OFFSET $2
 `
 
-type GetEntriesForValidationParams struct {
-	LastCheckedTime sql.NullTime `json:"last_checked_time"`
-	Limit           int32        `json:"limit"`
+type GetEntriesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
 }
 
-func (q *Queries) GetEntriesForValidation(ctx context.Context, arg GetEntriesForValidationParams) ([]Entry, error) {
-	rows, err := q.query(ctx, q.getEntriesForValidationStmt, getEntriesForValidation, arg.LastCheckedTime, arg.Limit)
+func (q *Queries) GetEntries(ctx context.Context, arg GetEntriesParams) ([]Entry, error) {
+	rows, err := q.query(ctx, q.getEntriesStmt, getEntries, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +115,12 @@ func (q *Queries) GetEntriesForValidation(ctx context.Context, arg GetEntriesFor
 			&i.LastCheckedTime,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ValidationStatus,
+			&i.ValidationDetail,
+			&i.NextCheckTime,
+			&i.CheckStreak,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -99,7 +136,7 @@ func (q *Queries) GetEntriesForValidation(ctx context.Context, arg GetEntriesFor
 }
 
 const getEntryByDID = `-- name: GetEntryByDID :one
-SELECT did, handle, is_valid, last_checked_time, created_at, updated_at
+SELECT did, handle, is_valid, last_checked_time, created_at, updated_at, validation_status, validation_detail, next_check_time, check_streak, lease_owner, lease_expires_at
 FROM entries
 WHERE did = $1
 `
@@ -114,12 +151,18 @@ func (q *Queries) GetEntryByDID(ctx context.Context, did string) (Entry, error)
 		&i.LastCheckedTime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ValidationStatus,
+		&i.ValidationDetail,
+		&i.NextCheckTime,
+		&i.CheckStreak,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
 	)
 	return i, err
 }
 
 const getEntryByHandle = `-- name: GetEntryByHandle :one
-SELECT did, handle, is_valid, last_checked_time, created_at, updated_at
+SELECT did, handle, is_valid, last_checked_time, created_at, updated_at, validation_status, validation_detail, next_check_time, check_streak, lease_owner, lease_expires_at
 FROM entries
 WHERE handle = $1
 `
@@ -134,26 +177,262 @@ func (q *Queries) GetEntryByHandle(ctx context.Context, handle string) (Entry, e
 		&i.LastCheckedTime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ValidationStatus,
+		&i.ValidationDetail,
+		&i.NextCheckTime,
+		&i.CheckStreak,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
 	)
 	return i, err
 }
 
-const updateEntriesValidation = `-- name: UpdateEntriesValidation :exec
+const getUnnormalizedEntryHandles = `-- name: GetUnnormalizedEntryHandles :many
+SELECT did,
+    handle
+FROM entries
+WHERE did > $1
+    AND handle <> ''
+    AND handle !~ '^[a-z0-9.-]*[a-z0-9-]$'
+ORDER BY did
+LIMIT $2
+`
+
+type GetUnnormalizedEntryHandlesParams struct {
+	After string `json:"after"`
+	Limit int32  `json:"limit"`
+}
+
+type GetUnnormalizedEntryHandlesRow struct {
+	Did    string `json:"did"`
+	Handle string `json:"handle"`
+}
+
+func (q *Queries) GetUnnormalizedEntryHandles(ctx context.Context, arg GetUnnormalizedEntryHandlesParams) ([]GetUnnormalizedEntryHandlesRow, error) {
+	rows, err := q.query(ctx, q.getUnnormalizedEntryHandlesStmt, getUnnormalizedEntryHandles, arg.After, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnnormalizedEntryHandlesRow
+	for rows.Next() {
+		var i GetUnnormalizedEntryHandlesRow
+		if err := rows.Scan(&i.Did, &i.Handle); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseEntryLeases = `-- name: ReleaseEntryLeases :exec
+UPDATE entries
+SET lease_owner = '',
+    lease_expires_at = NULL
+WHERE did = ANY($1::text [])
+    AND lease_owner = $2
+`
+
+type ReleaseEntryLeasesParams struct {
+	Dids       []string `json:"dids"`
+	LeaseOwner string   `json:"lease_owner"`
+}
+
+func (q *Queries) ReleaseEntryLeases(ctx context.Context, arg ReleaseEntryLeasesParams) error {
+	_, err := q.exec(ctx, q.releaseEntryLeasesStmt, releaseEntryLeases, pq.Array(arg.Dids), arg.LeaseOwner)
+	return err
+}
+
+const renameEntryHandles = `-- name: RenameEntryHandles :many
+UPDATE entries
+SET handle = v.new_handle
+FROM (
+        SELECT unnest($1::text []) AS did,
+            unnest($2::text []) AS old_handle,
+            unnest($3::text []) AS new_handle
+    ) AS v
+WHERE entries.did = v.did
+    AND entries.handle = v.old_handle
+RETURNING entries.did, entries.handle, entries.is_valid, entries.last_checked_time, entries.created_at, entries.updated_at, entries.validation_status, entries.validation_detail, entries.next_check_time, entries.check_streak, entries.lease_owner, entries.lease_expires_at
+`
+
+type RenameEntryHandlesParams struct {
+	Dids       []string `json:"dids"`
+	OldHandles []string `json:"old_handles"`
+	NewHandles []string `json:"new_handles"`
+}
+
+func (q *Queries) RenameEntryHandles(ctx context.Context, arg RenameEntryHandlesParams) ([]Entry, error) {
+	rows, err := q.query(ctx, q.renameEntryHandlesStmt, renameEntryHandles, pq.Array(arg.Dids), pq.Array(arg.OldHandles), pq.Array(arg.NewHandles))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entry
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.Did,
+			&i.Handle,
+			&i.IsValid,
+			&i.LastCheckedTime,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ValidationStatus,
+			&i.ValidationDetail,
+			&i.NextCheckTime,
+			&i.CheckStreak,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const renewEntryLeases = `-- name: RenewEntryLeases :exec
+UPDATE entries
+SET lease_expires_at = NOW() + $1::float8 * INTERVAL '1 second'
+WHERE did = ANY($2::text [])
+    AND lease_owner = $3
+`
+
+type RenewEntryLeasesParams struct {
+	LeaseSeconds float64  `json:"lease_seconds"`
+	Dids         []string `json:"dids"`
+	LeaseOwner   string   `json:"lease_owner"`
+}
+
+func (q *Queries) RenewEntryLeases(ctx context.Context, arg RenewEntryLeasesParams) error {
+	_, err := q.exec(ctx, q.renewEntryLeasesStmt, renewEntryLeases, arg.LeaseSeconds, pq.Array(arg.Dids), arg.LeaseOwner)
+	return err
+}
+
+const updateEntries = `-- name: UpdateEntries :exec
+INSERT INTO entries (did, handle, is_valid)
+SELECT unnest($1::text []),
+    unnest($2::text []),
+    FALSE ON CONFLICT (did) DO
+UPDATE
+SET handle = EXCLUDED.handle,
+    updated_at = EXCLUDED.updated_at,
+    next_check_time = CASE
+        WHEN entries.handle = EXCLUDED.handle
+        AND entries.validation_status NOT IN ('unclaimed', 'document_error') THEN entries.next_check_time
+    END,
+    check_streak = CASE
+        WHEN entries.handle = EXCLUDED.handle THEN entries.check_streak
+        ELSE 0
+    END
+WHERE entries.did = EXCLUDED.did
+`
+
+type UpdateEntriesParams struct {
+	Dids    []string `json:"dids"`
+	Handles []string `json:"handles"`
+}
+
+func (q *Queries) UpdateEntries(ctx context.Context, arg UpdateEntriesParams) error {
+	_, err := q.exec(ctx, q.updateEntriesStmt, updateEntries, pq.Array(arg.Dids), pq.Array(arg.Handles))
+	return err
+}
+
+const updateEntriesValidation = `-- name: UpdateEntriesValidation :many
 UPDATE entries
 SET last_checked_time = $1,
-    is_valid = $2
-WHERE did = ANY($3::text [])
+    is_valid = v.is_valid,
+    validation_status = v.validation_status,
+    validation_detail = v.validation_detail,
+    next_check_time = v.next_check_time,
+    check_streak = v.check_streak,
+    lease_owner = CASE
+        WHEN $2::text = '' THEN entries.lease_owner
+        ELSE ''
+    END,
+    lease_expires_at = CASE
+        WHEN $2::text = '' THEN entries.lease_expires_at
+    END
+FROM (
+        SELECT unnest($3::text []) AS did,
+            unnest($4::text []) AS handle,
+            unnest($5::boolean []) AS is_valid,
+            unnest($6::text []) AS validation_status,
+            unnest($7::text []) AS validation_detail,
+            unnest($8::timestamptz []) AS next_check_time,
+            unnest($9::integer []) AS check_streak
+    ) AS v
+WHERE entries.did = v.did
+    AND entries.handle = v.handle
+    AND (
+        $2::text = ''
+        OR entries.lease_owner = $2::text
+    )
+RETURNING entries.did,
+    entries.handle
 `
 
 type UpdateEntriesValidationParams struct {
 	LastCheckedTime sql.NullTime `json:"last_checked_time"`
-	IsValid         bool         `json:"is_valid"`
+	LeaseOwner      string       `json:"lease_owner"`
 	Dids            []string     `json:"dids"`
+	Handles         []string     `json:"handles"`
+	IsValids        []bool       `json:"is_valids"`
+	Statuses        []string     `json:"statuses"`
+	Details         []string     `json:"details"`
+	NextCheckTimes  []time.Time  `json:"next_check_times"`
+	CheckStreaks    []int32      `json:"check_streaks"`
 }
 
-func (q *Queries) UpdateEntriesValidation(ctx context.Context, arg UpdateEntriesValidationParams) error {
-	_, err := q.exec(ctx, q.updateEntriesValidationStmt, updateEntriesValidation, arg.LastCheckedTime, arg.IsValid, pq.Array(arg.Dids))
-	return err
+type UpdateEntriesValidationRow struct {
+	Did    string `json:"did"`
+	Handle string `json:"handle"`
+}
+
+func (q *Queries) UpdateEntriesValidation(ctx context.Context, arg UpdateEntriesValidationParams) ([]UpdateEntriesValidationRow, error) {
+	rows, err := q.query(ctx, q.updateEntriesValidationStmt, updateEntriesValidation,
+		arg.LastCheckedTime,
+		arg.LeaseOwner,
+		pq.Array(arg.Dids),
+		pq.Array(arg.Handles),
+		pq.Array(arg.IsValids),
+		pq.Array(arg.Statuses),
+		pq.Array(arg.Details),
+		pq.Array(arg.NextCheckTimes),
+		pq.Array(arg.CheckStreaks),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UpdateEntriesValidationRow
+	for rows.Next() {
+		var i UpdateEntriesValidationRow
+		if err := rows.Scan(&i.Did, &i.Handle); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const updateEntry = `-- name: UpdateEntry :exec
@@ -161,7 +440,14 @@ INSERT INTO entries (did, handle, is_valid, last_checked_time)
 VALUES ($1, $2, $3, $4) ON CONFLICT (did) DO
 UPDATE
 SET handle = EXCLUDED.handle,
-    updated_at = EXCLUDED.updated_at
+    updated_at = EXCLUDED.updated_at,
+    next_check_time = CASE
+        WHEN entries.handle = EXCLUDED.handle THEN entries.next_check_time
+    END,
+    check_streak = CASE
+        WHEN entries.handle = EXCLUDED.handle THEN entries.check_streak
+        ELSE 0
+    END
 WHERE entries.did = EXCLUDED.did
 `
 
@@ -6,14 +6,48 @@ package store_queries
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type Cursor struct {
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DidDocument struct {
+	Did                 string          `json:"did"`
+	AlsoKnownAs         []string        `json:"also_known_as"`
+	VerificationMethods json.RawMessage `json:"verification_methods"`
+	RotationKeys        []string        `json:"rotation_keys"`
+	Services            json.RawMessage `json:"services"`
+	ChainInvalid        bool            `json:"chain_invalid"`
+	ChainError          string          `json:"chain_error"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
 type Entry struct {
-	Did             string       `json:"did"`
-	Handle          string       `json:"handle"`
-	IsValid         bool         `json:"is_valid"`
-	LastCheckedTime sql.NullTime `json:"last_checked_time"`
-	CreatedAt       time.Time    `json:"created_at"`
-	UpdatedAt       sql.NullTime `json:"updated_at"`
+	Did              string       `json:"did"`
+	Handle           string       `json:"handle"`
+	IsValid          bool         `json:"is_valid"`
+	LastCheckedTime  sql.NullTime `json:"last_checked_time"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        sql.NullTime `json:"updated_at"`
+	ValidationStatus string       `json:"validation_status"`
+	ValidationDetail string       `json:"validation_detail"`
+	NextCheckTime    sql.NullTime `json:"next_check_time"`
+	CheckStreak      int32        `json:"check_streak"`
+	LeaseOwner       string       `json:"lease_owner"`
+	LeaseExpiresAt   sql.NullTime `json:"lease_expires_at"`
+}
+
+type PlcOperation struct {
+	Cid        string          `json:"cid"`
+	Did        string          `json:"did"`
+	Handle     string          `json:"handle"`
+	Operation  json.RawMessage `json:"operation"`
+	Nullified  bool            `json:"nullified"`
+	CreatedAt  time.Time       `json:"created_at"`
+	InsertedAt time.Time       `json:"inserted_at"`
 }
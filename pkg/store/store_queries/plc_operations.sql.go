@@ -0,0 +1,306 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: plc_operations.sql
+
+package store_queries
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const getHandleClaimsByDID = `-- name: GetHandleClaimsByDID :many
+SELECT cid, did, handle, operation, nullified, created_at, inserted_at
+FROM plc_operations
+WHERE did = $1
+    AND handle <> ''
+    AND NOT nullified
+ORDER BY created_at
+`
+
+func (q *Queries) GetHandleClaimsByDID(ctx context.Context, did string) ([]PlcOperation, error) {
+	rows, err := q.query(ctx, q.getHandleClaimsByDIDStmt, getHandleClaimsByDID, did)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlcOperation
+	for rows.Next() {
+		var i PlcOperation
+		if err := rows.Scan(
+			&i.Cid,
+			&i.Did,
+			&i.Handle,
+			&i.Operation,
+			&i.Nullified,
+			&i.CreatedAt,
+			&i.InsertedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHandleClaimsByHandle = `-- name: GetHandleClaimsByHandle :many
+SELECT cid, did, handle, operation, nullified, created_at, inserted_at
+FROM plc_operations
+WHERE handle = $1
+    AND NOT nullified
+ORDER BY created_at
+`
+
+func (q *Queries) GetHandleClaimsByHandle(ctx context.Context, handle string) ([]PlcOperation, error) {
+	rows, err := q.query(ctx, q.getHandleClaimsByHandleStmt, getHandleClaimsByHandle, handle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlcOperation
+	for rows.Next() {
+		var i PlcOperation
+		if err := rows.Scan(
+			&i.Cid,
+			&i.Did,
+			&i.Handle,
+			&i.Operation,
+			&i.Nullified,
+			&i.CreatedAt,
+			&i.InsertedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPLCOperationByCID = `-- name: GetPLCOperationByCID :one
+SELECT cid, did, handle, operation, nullified, created_at, inserted_at
+FROM plc_operations
+WHERE cid = $1
+`
+
+func (q *Queries) GetPLCOperationByCID(ctx context.Context, cid string) (PlcOperation, error) {
+	row := q.queryRow(ctx, q.getPLCOperationByCIDStmt, getPLCOperationByCID, cid)
+	var i PlcOperation
+	err := row.Scan(
+		&i.Cid,
+		&i.Did,
+		&i.Handle,
+		&i.Operation,
+		&i.Nullified,
+		&i.CreatedAt,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const getPLCOperationsByDID = `-- name: GetPLCOperationsByDID :many
+SELECT cid, did, handle, operation, nullified, created_at, inserted_at
+FROM plc_operations
+WHERE did = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetPLCOperationsByDID(ctx context.Context, did string) ([]PlcOperation, error) {
+	rows, err := q.query(ctx, q.getPLCOperationsByDIDStmt, getPLCOperationsByDID, did)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlcOperation
+	for rows.Next() {
+		var i PlcOperation
+		if err := rows.Scan(
+			&i.Cid,
+			&i.Did,
+			&i.Handle,
+			&i.Operation,
+			&i.Nullified,
+			&i.CreatedAt,
+			&i.InsertedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnnormalizedOperationHandles = `-- name: GetUnnormalizedOperationHandles :many
+SELECT cid,
+    handle
+FROM plc_operations
+WHERE cid > $1
+    AND handle <> ''
+    AND handle !~ '^[a-z0-9.-]*[a-z0-9-]$'
+ORDER BY cid
+LIMIT $2
+`
+
+type GetUnnormalizedOperationHandlesParams struct {
+	After string `json:"after"`
+	Limit int32  `json:"limit"`
+}
+
+type GetUnnormalizedOperationHandlesRow struct {
+	Cid    string `json:"cid"`
+	Handle string `json:"handle"`
+}
+
+func (q *Queries) GetUnnormalizedOperationHandles(ctx context.Context, arg GetUnnormalizedOperationHandlesParams) ([]GetUnnormalizedOperationHandlesRow, error) {
+	rows, err := q.query(ctx, q.getUnnormalizedOperationHandlesStmt, getUnnormalizedOperationHandles, arg.After, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnnormalizedOperationHandlesRow
+	for rows.Next() {
+		var i GetUnnormalizedOperationHandlesRow
+		if err := rows.Scan(&i.Cid, &i.Handle); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const renameOperationHandles = `-- name: RenameOperationHandles :exec
+UPDATE plc_operations
+SET handle = v.new_handle
+FROM (
+        SELECT unnest($1::text []) AS cid,
+            unnest($2::text []) AS old_handle,
+            unnest($3::text []) AS new_handle
+    ) AS v
+WHERE plc_operations.cid = v.cid
+    AND plc_operations.handle = v.old_handle
+`
+
+type RenameOperationHandlesParams struct {
+	Cids       []string `json:"cids"`
+	OldHandles []string `json:"old_handles"`
+	NewHandles []string `json:"new_handles"`
+}
+
+func (q *Queries) RenameOperationHandles(ctx context.Context, arg RenameOperationHandlesParams) error {
+	_, err := q.exec(ctx, q.renameOperationHandlesStmt, renameOperationHandles, pq.Array(arg.Cids), pq.Array(arg.OldHandles), pq.Array(arg.NewHandles))
+	return err
+}
+
+const upsertPLCOperation = `-- name: UpsertPLCOperation :execrows
+INSERT INTO plc_operations (cid, did, handle, operation, nullified, created_at)
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (cid) DO
+UPDATE
+SET nullified = EXCLUDED.nullified
+WHERE plc_operations.cid = EXCLUDED.cid
+    AND plc_operations.nullified <> EXCLUDED.nullified
+`
+
+type UpsertPLCOperationParams struct {
+	Cid       string          `json:"cid"`
+	Did       string          `json:"did"`
+	Handle    string          `json:"handle"`
+	Operation json.RawMessage `json:"operation"`
+	Nullified bool            `json:"nullified"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (q *Queries) UpsertPLCOperation(ctx context.Context, arg UpsertPLCOperationParams) (int64, error) {
+	result, err := q.exec(ctx, q.upsertPLCOperationStmt, upsertPLCOperation,
+		arg.Cid,
+		arg.Did,
+		arg.Handle,
+		arg.Operation,
+		arg.Nullified,
+		arg.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertPLCOperations = `-- name: UpsertPLCOperations :many
+INSERT INTO plc_operations (cid, did, handle, operation, nullified, created_at)
+SELECT unnest($1::text []),
+    unnest($2::text []),
+    unnest($3::text []),
+    unnest($4::text [])::jsonb,
+    unnest($5::boolean []),
+    unnest($6::timestamptz []) ON CONFLICT (cid) DO
+UPDATE
+SET nullified = EXCLUDED.nullified
+WHERE plc_operations.cid = EXCLUDED.cid
+    AND plc_operations.nullified <> EXCLUDED.nullified
+RETURNING cid
+`
+
+type UpsertPLCOperationsParams struct {
+	Cids       []string    `json:"cids"`
+	Dids       []string    `json:"dids"`
+	Handles    []string    `json:"handles"`
+	Operations []string    `json:"operations"`
+	Nullified  []bool      `json:"nullified"`
+	CreatedAts []time.Time `json:"created_ats"`
+}
+
+func (q *Queries) UpsertPLCOperations(ctx context.Context, arg UpsertPLCOperationsParams) ([]string, error) {
+	rows, err := q.query(ctx, q.upsertPLCOperationsStmt, upsertPLCOperations,
+		pq.Array(arg.Cids),
+		pq.Array(arg.Dids),
+		pq.Array(arg.Handles),
+		pq.Array(arg.Operations),
+		pq.Array(arg.Nullified),
+		pq.Array(arg.CreatedAts),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var cid string
+		if err := rows.Scan(&cid); err != nil {
+			return nil, err
+		}
+		items = append(items, cid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
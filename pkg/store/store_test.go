@@ -0,0 +1,299 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ericvolp12/bingo/pkg/redistest"
+	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+// queryFunc answers a query sent to the fake database with columns and rows
+type queryFunc func(query string, args []driver.Value) ([]string, [][]driver.Value, error)
+
+var (
+	fakeDBs  = map[string]queryFunc{}
+	fakeDBID atomic.Int64
+)
+
+func init() {
+	sql.Register("bingotest", fakeDriver{})
+}
+
+// openFakeDB returns a database that answers every query with fn
+func openFakeDB(t *testing.T, fn queryFunc) *sql.DB {
+	t.Helper()
+	name := fmt.Sprintf("%s_%d", t.Name(), fakeDBID.Add(1))
+	fakeDBs[name] = fn
+	db, err := sql.Open("bingotest", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fn, ok := fakeDBs[name]
+	if !ok {
+		return nil, errors.New("unknown fake database")
+	}
+	return &fakeConn{fn: fn}, nil
+}
+
+type fakeConn struct{ fn queryFunc }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{fn: c.fn, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions aren't supported")
+}
+
+type fakeStmt struct {
+	fn    queryFunc
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	_, _, err := s.fn(s.query, args)
+	return driver.RowsAffected(0), err
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	columns, rows, err := s.fn(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: columns, rows: rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// stringArray decodes a text array argument
+func stringArray(t *testing.T, v driver.Value) []string {
+	t.Helper()
+	var values []string
+	if err := pq.Array(&values).Scan(v); err != nil {
+		t.Fatalf("failed to decode array argument %v: %v", v, err)
+	}
+	return values
+}
+
+func TestBulkUpdateEntryValidationSkipsChangedHandles(t *testing.T) {
+	const owner = "worker-1"
+
+	// The rows as they are in postgres, did:plc:moved was re-pointed to a new handle while its old one was validated
+	rows := map[string]struct{ handle, leaseOwner string }{
+		"did:plc:kept":   {"kept.test", owner},
+		"did:plc:moved":  {"new.test", owner},
+		"did:plc:stolen": {"stolen.test", "worker-2"},
+	}
+
+	db := openFakeDB(t, func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		if !strings.Contains(query, "name: UpdateEntriesValidation") {
+			return nil, nil, errors.New("unexpected query")
+		}
+		if !strings.Contains(query, "entries.handle = v.handle") {
+			t.Error("UpdateEntriesValidation doesn't match on the validated handle")
+		}
+
+		// $2 is the lease owner, $3 and $4 the DIDs and the handles they were validated under
+		leaseOwner := args[1].(string)
+		dids, handles := stringArray(t, args[2]), stringArray(t, args[3])
+		var updated [][]driver.Value
+		for i, did := range dids {
+			row := rows[did]
+			if row.handle == handles[i] && (leaseOwner == "" || row.leaseOwner == leaseOwner) {
+				updated = append(updated, []driver.Value{did, row.handle})
+			}
+		}
+		return []string{"did", "handle"}, updated, nil
+	})
+
+	srv := redistest.NewServer(t)
+	s := &Store{
+		RedisPrefix: "bingo",
+		Redis:       srv.Client(t),
+		DB:          db,
+		Queries:     store_queries.New(db),
+	}
+	ctx := context.Background()
+
+	err := s.BulkUpdateEntryValidation(ctx, owner, []*Entry{
+		{Did: "did:plc:kept", Handle: "kept.test", IsValid: true, ValidationStatus: ValidationStatusValid},
+		{Did: "did:plc:moved", Handle: "old.test", IsValid: true, ValidationStatus: ValidationStatusValid},
+		{Did: "did:plc:stolen", Handle: "stolen.test", IsValid: true, ValidationStatus: ValidationStatusValid},
+	})
+	if err != nil {
+		t.Fatalf("BulkUpdateEntryValidation: %v", err)
+	}
+
+	val, err := s.Redis.Get(ctx, "bingo_d_did:plc:kept").Result()
+	if err != nil {
+		t.Fatalf("the updated entry wasn't cached: %v", err)
+	}
+	entry := &Entry{}
+	if err := json.Unmarshal([]byte(val), entry); err != nil || !entry.IsValid {
+		t.Errorf("cached entry = %s, %v", val, err)
+	}
+	if _, err := s.Redis.Get(ctx, "bingo_h_kept.test").Result(); err != nil {
+		t.Errorf("the updated entry's handle wasn't cached: %v", err)
+	}
+
+	// The stale result for the old handle must not bring its lookup key back
+	for _, key := range []string{"bingo_h_old.test", "bingo_d_did:plc:moved", "bingo_h_stolen.test", "bingo_d_did:plc:stolen"} {
+		if err := s.Redis.Get(ctx, key).Err(); !errors.Is(err, redis.Nil) {
+			t.Errorf("%s was written for a row the update skipped: %v", key, err)
+		}
+	}
+}
+
+func TestNormalizeHandles(t *testing.T) {
+	// Handles as older versions stored them, keyed by DID and by CID
+	entryHandles := map[string]string{
+		"did:plc:upper":   "Alice.Test",
+		"did:plc:dot":     "bob.test.",
+		"did:plc:unicode": "bücher.de",
+		"did:plc:invalid": "not a handle",
+	}
+	operationHandles := map[string]string{
+		"cid-1": "Alice.Test",
+		"cid-2": "bücher.de",
+		"cid-3": "_invalid.test",
+	}
+
+	// unnormalized pages the rows after the cursor that the queries' pattern flags
+	normalizedPattern := regexp.MustCompile(`^[a-z0-9.-]*[a-z0-9-]$`)
+	unnormalized := func(rows map[string]string, after string) [][]driver.Value {
+		var page [][]driver.Value
+		for _, key := range sortedKeys(rows) {
+			if key > after && !normalizedPattern.MatchString(rows[key]) {
+				page = append(page, []driver.Value{key, rows[key]})
+			}
+		}
+		return page
+	}
+	rename := func(rows map[string]string, keys, oldHandles, newHandles []string) []string {
+		var renamed []string
+		for i, key := range keys {
+			if rows[key] == oldHandles[i] {
+				rows[key] = newHandles[i]
+				renamed = append(renamed, key)
+			}
+		}
+		return renamed
+	}
+
+	db := openFakeDB(t, func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		switch {
+		case strings.Contains(query, "name: GetUnnormalizedEntryHandles"):
+			return []string{"did", "handle"}, unnormalized(entryHandles, args[0].(string)), nil
+		case strings.Contains(query, "name: GetUnnormalizedOperationHandles"):
+			return []string{"cid", "handle"}, unnormalized(operationHandles, args[0].(string)), nil
+		case strings.Contains(query, "name: RenameEntryHandles"):
+			var rows [][]driver.Value
+			for _, did := range rename(entryHandles, stringArray(t, args[0]), stringArray(t, args[1]), stringArray(t, args[2])) {
+				rows = append(rows, []driver.Value{did, entryHandles[did], true, nil, time.Now(), nil, "valid", "", nil, int64(0), "", nil})
+			}
+			return []string{"did", "handle", "is_valid", "last_checked_time", "created_at", "updated_at",
+				"validation_status", "validation_detail", "next_check_time", "check_streak", "lease_owner", "lease_expires_at"}, rows, nil
+		case strings.Contains(query, "name: RenameOperationHandles"):
+			rename(operationHandles, stringArray(t, args[0]), stringArray(t, args[1]), stringArray(t, args[2]))
+			return nil, nil, nil
+		}
+		return nil, nil, errors.New("unexpected query")
+	})
+
+	srv := redistest.NewServer(t)
+	s := &Store{
+		RedisPrefix: "bingo",
+		Redis:       srv.Client(t),
+		DB:          db,
+		Queries:     store_queries.New(db),
+	}
+	ctx := context.Background()
+
+	// A key an older server wrote under the unnormalized handle
+	if err := s.Redis.Set(ctx, "bingo_h_Alice.Test", "{}", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, operations, err := s.NormalizeHandles(ctx)
+	if err != nil {
+		t.Fatalf("NormalizeHandles: %v", err)
+	}
+	if entries != 3 || operations != 2 {
+		t.Errorf("NormalizeHandles rewrote %d entries and %d operations, want 3 and 2", entries, operations)
+	}
+
+	wantEntries := map[string]string{
+		"did:plc:upper":   "alice.test",
+		"did:plc:dot":     "bob.test",
+		"did:plc:unicode": "xn--bcher-kva.de",
+		"did:plc:invalid": "not a handle",
+	}
+	for did, want := range wantEntries {
+		if entryHandles[did] != want {
+			t.Errorf("handle of %s = %q, want %q", did, entryHandles[did], want)
+		}
+	}
+	if operationHandles["cid-1"] != "alice.test" || operationHandles["cid-2"] != "xn--bcher-kva.de" || operationHandles["cid-3"] != "_invalid.test" {
+		t.Errorf("operation handles = %v", operationHandles)
+	}
+
+	if err := s.Redis.Get(ctx, "bingo_h_Alice.Test").Err(); !errors.Is(err, redis.Nil) {
+		t.Errorf("the key of the old handle is still there: %v", err)
+	}
+	for _, key := range []string{"bingo_h_alice.test", "bingo_h_xn--bcher-kva.de", "bingo_d_did:plc:dot"} {
+		if err := s.Redis.Get(ctx, key).Err(); err != nil {
+			t.Errorf("%s wasn't cached: %v", key, err)
+		}
+	}
+
+	// Running it again finds nothing left to do
+	entries, operations, err = s.NormalizeHandles(ctx)
+	if err != nil || entries != 0 || operations != 0 {
+		t.Errorf("second NormalizeHandles = %d, %d, %v, want nothing rewritten", entries, operations, err)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
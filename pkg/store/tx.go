@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericvolp12/bingo/pkg/store/store_queries"
+)
+
+// Tx groups postgres writes into a single transaction.
+// Redis is only a cache of postgres, so cache writes are deferred until the transaction commits.
+type Tx struct {
+	store *Store
+	tx    *sql.Tx
+
+	queries     *store_queries.Queries
+	afterCommit []func(ctx context.Context) error
+}
+
+// Begin starts a transaction, callers must Commit or Rollback it
+func (s *Store) Begin(ctx context.Context) (*Tx, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bingo: failed to begin transaction: %w", err)
+	}
+
+	return &Tx{
+		store:   s,
+		tx:      tx,
+		queries: s.Queries.WithTx(tx),
+	}, nil
+}
+
+// ErrCacheWrite is returned by Commit when the transaction committed but updating redis failed
+var ErrCacheWrite = errors.New("bingo: failed to update cache after commit")
+
+// Commit commits the transaction and then applies the deferred cache writes.
+// Cache write failures are wrapped in ErrCacheWrite, they don't undo the commit.
+func (t *Tx) Commit(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "Commit")
+	defer span.End()
+
+	if err := t.tx.Commit(); err != nil {
+		return fmt.Errorf("bingo: failed to commit transaction: %w", err)
+	}
+
+	var cacheErr error
+	for _, fn := range t.afterCommit {
+		if err := fn(ctx); err != nil && cacheErr == nil {
+			cacheErr = fmt.Errorf("%w: %w", ErrCacheWrite, err)
+		}
+	}
+
+	return cacheErr
+}
+
+// Rollback aborts the transaction, dropping the deferred cache writes.
+// It's safe to call after Commit.
+func (t *Tx) Rollback() error {
+	t.afterCommit = nil
+
+	err := t.tx.Rollback()
+	if err != nil && err != sql.ErrTxDone {
+		return fmt.Errorf("bingo: failed to roll back transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RecordOperations persists a batch of PLC operations to the operation log within the transaction
+func (t *Tx) RecordOperations(ctx context.Context, ops []*Operation) (map[string]bool, error) {
+	return recordOperations(ctx, t.queries, ops)
+}
+
+// GetOperation returns a single PLC operation by CID within the transaction, including operations it recorded
+func (t *Tx) GetOperation(ctx context.Context, cid string) (*Operation, error) {
+	return getOperation(ctx, t.queries, cid)
+}
+
+// GetOperationsForDID returns the operation log for a DID within the transaction, including operations it recorded
+func (t *Tx) GetOperationsForDID(ctx context.Context, did string) ([]*Operation, error) {
+	return getOperationsForDID(ctx, t.queries, did)
+}
+
+// UpdateDIDDocuments replaces the stored identity data for many DIDs within the transaction
+func (t *Tx) UpdateDIDDocuments(ctx context.Context, docs []*DIDDocument) error {
+	return updateDIDDocuments(ctx, t.queries, docs)
+}
+
+// BulkUpdate upserts the handles of many entries within the transaction, caching them once it commits
+func (t *Tx) BulkUpdate(ctx context.Context, entries []*Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := updateEntries(ctx, t.queries, entries); err != nil {
+		return err
+	}
+
+	t.afterCommit = append(t.afterCommit, func(ctx context.Context) error {
+		return t.store.cacheEntries(ctx, entries)
+	})
+
+	return nil
+}
+
+// Delete deletes an entry and its DID document within the transaction, evicting it from the cache once it commits
+func (t *Tx) Delete(ctx context.Context, did string) error {
+	if err := deleteEntry(ctx, t.queries, did); err != nil {
+		return err
+	}
+
+	t.afterCommit = append(t.afterCommit, func(ctx context.Context) error {
+		return t.store.uncacheEntry(ctx, did)
+	})
+
+	return nil
+}
+
+// FlagDIDChainInvalid marks a DID whose PLC operation chain failed verification within the transaction
+func (t *Tx) FlagDIDChainInvalid(ctx context.Context, did string, reason string) error {
+	return flagDIDChainInvalid(ctx, t.queries, did, reason)
+}
+
+// SetCursor stores the value of a named ingestion cursor within the transaction
+func (t *Tx) SetCursor(ctx context.Context, name string, value string) error {
+	return setCursor(ctx, t.queries, name, value)
+}
@@ -0,0 +1,65 @@
+package syntax
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidDID = errors.New("invalid DID")
+
+// maxDIDLength is the longest DID ATProto accepts
+const maxDIDLength = 2048
+
+var (
+	// didRegex is the generic DID syntax ATProto accepts, see https://atproto.com/specs/did
+	didRegex = regexp.MustCompile(`^did:[a-z]+:[a-zA-Z0-9._:%-]*[a-zA-Z0-9._-]$`)
+	// plcRegex is the did:plc syntax, 24 characters of lowercase base32
+	plcRegex = regexp.MustCompile(`^did:plc:[a-z2-7]{24}$`)
+)
+
+// ValidateDID checks a DID's generic syntax and the method specific syntax of did:plc and did:web,
+// the only methods ATProto supports
+func ValidateDID(did string) error {
+	if len(did) > maxDIDLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidDID, maxDIDLength)
+	}
+	if !didRegex.MatchString(did) {
+		return fmt.Errorf("%w %q", ErrInvalidDID, did)
+	}
+
+	switch {
+	case strings.HasPrefix(did, "did:plc:"):
+		if !plcRegex.MatchString(did) {
+			return fmt.Errorf("%w %q: did:plc identifiers are 24 characters of lowercase base32", ErrInvalidDID, did)
+		}
+	case strings.HasPrefix(did, "did:web:"):
+		// The first segment is the host, any further ones are the path to the document
+		host, _, _ := strings.Cut(strings.TrimPrefix(did, "did:web:"), ":")
+		host, err := url.PathUnescape(host)
+		if err != nil {
+			return fmt.Errorf("%w %q: %s", ErrInvalidDID, did, err.Error())
+		}
+		if err := checkHostname(host); err != nil {
+			return fmt.Errorf("%w %q: invalid host: %s", ErrInvalidDID, did, err.Error())
+		}
+	default:
+		method := strings.Split(did, ":")[1]
+		return fmt.Errorf("%w %q: unsupported method did:%s", ErrInvalidDID, did, method)
+	}
+
+	return nil
+}
+
+// NormalizeIdentifier returns the canonical form of a handle, or the DID itself if it's a valid DID
+func NormalizeIdentifier(handleOrDid string) (string, error) {
+	if strings.HasPrefix(handleOrDid, "did:") {
+		if err := ValidateDID(handleOrDid); err != nil {
+			return "", err
+		}
+		return handleOrDid, nil
+	}
+	return NormalizeHandle(handleOrDid)
+}
@@ -0,0 +1,84 @@
+package syntax
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateDID(t *testing.T) {
+	tests := []struct {
+		did   string
+		valid bool
+	}{
+		{"did:plc:ewvi7nxzyoun6zhxrhs64oiz", true},
+		{"did:plc:aaaaaaaaaaaaaaaaaaaaaaaa", true},
+		{"did:web:example.com", true},
+		{"did:web:alice.bsky.social", true},
+		{"did:web:localhost%3A8080", false},
+		{"did:web:example.com:users:alice", true},
+		{"did:web:xn--bcher-kva.de", true},
+
+		// did:plc is exactly 24 characters of lowercase base32
+		{"did:plc:ewvi7nxzyoun6zhxrhs64oi", false},
+		{"did:plc:ewvi7nxzyoun6zhxrhs64oizz", false},
+		{"did:plc:EWVI7NXZYOUN6ZHXRHS64OIZ", false},
+		{"did:plc:ewvi7nxzyoun6zhxrhs64oi1", false},
+		{"did:plc:", false},
+		// did:web needs a valid host
+		{"did:web:", false},
+		{"did:web:localhost", false},
+		{"did:web:-example.com", false},
+		{"did:web:exa_mple.com", false},
+		// Generic syntax
+		{"did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme", false},
+		{"did:PLC:ewvi7nxzyoun6zhxrhs64oiz", false},
+		{"did:plc:ewvi7nxzyoun6zhxrhs64oiz:", false},
+		{"did:plc:ewvi7nxzyoun6zhxrhs64oiz#key", false},
+		{"DID:plc:ewvi7nxzyoun6zhxrhs64oiz", false},
+		{"plc:ewvi7nxzyoun6zhxrhs64oiz", false},
+		{"alice.test", false},
+		{"did:web:" + strings.Repeat("a", 2048) + ".com", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateDID(tt.did)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateDID(%q): %v", tt.did, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidDID) {
+			t.Errorf("ValidateDID(%q) = %v, want ErrInvalidDID", tt.did, err)
+		}
+	}
+}
+
+func TestNormalizeIdentifier(t *testing.T) {
+	tests := []struct {
+		identifier string
+		want       string
+		err        error
+	}{
+		{"did:plc:ewvi7nxzyoun6zhxrhs64oiz", "did:plc:ewvi7nxzyoun6zhxrhs64oiz", nil},
+		{"did:web:example.com", "did:web:example.com", nil},
+		// DIDs are case sensitive and aren't normalized, only validated
+		{"did:plc:EWVI7NXZYOUN6ZHXRHS64OIZ", "", ErrInvalidDID},
+		{"did:nope", "", ErrInvalidDID},
+		{"Alice.Test.", "alice.test", nil},
+		{"bücher.de", "xn--bcher-kva.de", nil},
+		{"alice.local", "", ErrInvalidHandle},
+		{"", "", ErrInvalidHandle},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeIdentifier(tt.identifier)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("NormalizeIdentifier(%q) = %q, %v, want %v", tt.identifier, got, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeIdentifier(%q) = %q, %v, want %q", tt.identifier, got, err, tt.want)
+		}
+	}
+}
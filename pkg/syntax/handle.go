@@ -0,0 +1,89 @@
+package syntax
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidHandle = errors.New("invalid handle")
+
+// maxHandleLength is the longest a handle can be once normalized, the limit for a DNS name
+const maxHandleLength = 253
+
+// disallowedTLDs are the TLDs handles can't use, see https://atproto.com/specs/handle
+var disallowedTLDs = map[string]bool{
+	"alt":       true,
+	"arpa":      true,
+	"example":   true,
+	"internal":  true,
+	"invalid":   true,
+	"local":     true,
+	"localhost": true,
+	"onion":     true,
+}
+
+// idnaProfile maps internationalized labels to their lowercase punycode form the way a DNS lookup would
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+)
+
+// NormalizeHandle returns the canonical form of a handle, lowercase with internationalized labels
+// converted to punycode and without the trailing dot of a fully qualified name, or an error if it
+// isn't a valid ATProto handle
+func NormalizeHandle(handle string) (string, error) {
+	normalized, err := idnaProfile.ToASCII(strings.TrimSuffix(strings.TrimSpace(handle), "."))
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %s", ErrInvalidHandle, handle, err.Error())
+	}
+	normalized = strings.ToLower(normalized)
+
+	if err := checkHostname(normalized); err != nil {
+		return "", fmt.Errorf("%w %q: %s", ErrInvalidHandle, handle, err.Error())
+	}
+
+	tld := normalized[strings.LastIndex(normalized, ".")+1:]
+	if disallowedTLDs[tld] {
+		return "", fmt.Errorf("%w %q: .%s isn't allowed", ErrInvalidHandle, handle, tld)
+	}
+
+	return normalized, nil
+}
+
+// checkHostname checks that a name is a fully qualified ASCII hostname: at least two labels of letters,
+// digits and inner hyphens, and a TLD that doesn't start with a digit
+func checkHostname(name string) error {
+	if len(name) > maxHandleLength {
+		return fmt.Errorf("longer than %d characters", maxHandleLength)
+	}
+
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("needs at least two labels")
+	}
+
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 {
+			return fmt.Errorf("labels must be 1 to 63 characters long")
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("labels can't start or end with a hyphen")
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return fmt.Errorf("invalid character %q", c)
+			}
+		}
+	}
+
+	if tld := labels[len(labels)-1]; tld[0] >= '0' && tld[0] <= '9' {
+		return fmt.Errorf("the TLD can't start with a digit")
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package syntax
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeHandle(t *testing.T) {
+	tests := []struct {
+		handle string
+		want   string
+	}{
+		{"alice.bsky.social", "alice.bsky.social"},
+		{"Alice.Bsky.Social", "alice.bsky.social"},
+		{"  alice.test ", "alice.test"},
+		// The trailing dot of a fully qualified name is dropped
+		{"alice.test.", "alice.test"},
+		{"ALICE.TEST.", "alice.test"},
+		// Internationalized labels become lowercase punycode, and punycode is kept as it is
+		{"bücher.de", "xn--bcher-kva.de"},
+		{"BÜCHER.de", "xn--bcher-kva.de"},
+		{"xn--bcher-kva.de", "xn--bcher-kva.de"},
+		{"例え.jp", "xn--r8jz45g.jp"},
+		{"a-b.c-d.test", "a-b.c-d.test"},
+		{"8.cn", "8.cn"},
+		{strings.Repeat("a", 63) + ".test", strings.Repeat("a", 63) + ".test"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeHandle(tt.handle)
+		if err != nil {
+			t.Errorf("NormalizeHandle(%q): %v", tt.handle, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeHandle(%q) = %q, want %q", tt.handle, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeHandleInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		".",
+		"alice",
+		"alice.test..",
+		"alice..test",
+		".alice.test",
+		"-alice.test",
+		"alice-.test",
+		"_atproto.alice.test",
+		"alice smith.test",
+		"alice.1test",
+		"alice.123",
+		"did:plc:ewvi7nxzyoun6zhxrhs64oiz",
+		// Labels are at most 63 characters and names at most 253
+		strings.Repeat("a", 64) + ".test",
+		strings.Repeat(strings.Repeat("a", 60)+".", 5) + "test",
+		// Disallowed TLDs
+		"alice.local",
+		"alice.localhost",
+		"alice.arpa",
+		"alice.onion",
+		"alice.internal",
+		"alice.invalid",
+		"alice.example",
+		"alice.alt",
+		"ALICE.LOCAL.",
+	}
+
+	for _, handle := range tests {
+		got, err := NormalizeHandle(handle)
+		if !errors.Is(err, ErrInvalidHandle) {
+			t.Errorf("NormalizeHandle(%q) = %q, %v, want ErrInvalidHandle", handle, got, err)
+		}
+	}
+}